@@ -0,0 +1,215 @@
+package disgobed
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+)
+
+/*
+Attachment is a handle to a file held by an Attachments registry. Pass it to a setter such as
+ImageBuilder.SetAttachment to show the file in an embed
+*/
+type Attachment struct {
+	name   string
+	reader io.Reader
+	// data holds the contents of attachments added as bytes, which are read afresh every time the files are returned
+	data []byte
+}
+
+/*
+Name returns the file name the attachment will be uploaded with. This may differ from the name it was added with if
+that name had to be changed to be unique or to be referenced by an `attachment://` url
+*/
+func (a *Attachment) Name() string {
+	return a.name
+}
+
+/*
+URL returns the `attachment://` url that references the attachment
+*/
+func (a *Attachment) URL() string {
+	return validation.AttachmentURLPrefix + a.name
+}
+
+/*
+Attachments is a registry of files to upload alongside a message. Adding a file returns a handle that produces the
+matching `attachment://` url, so embeds never reference a file that is not attached
+
+	files := NewAttachments()
+	graph := files.AddBytes(`graph.png`, png)
+	embed := NewEmbed().SetImage(NewImage().SetAttachment(graph))
+	params, errs := NewMessage().AddEmbed(embed).SetAttachments(files).Finalize()
+
+File names are made unique and safe to reference as they are added, such that a second `graph.png` becomes
+`graph_1.png`
+*/
+type Attachments struct {
+	files []*Attachment
+	names map[string]bool
+}
+
+/*
+NewAttachments creates and returns an empty attachment registry
+*/
+func NewAttachments() *Attachments {
+	return &Attachments{names: map[string]bool{}}
+}
+
+/*
+Add registers the contents of r under the given file name and returns a handle to it. Characters discord does not
+allow in `attachment://` urls are replaced with `_`, names without an extension have `.bin` added, and names that are
+already taken have a number added. r is read as the file is uploaded, so it can only be sent once. Use AddBytes for
+files the registry should be able to send more than once, such as when a send is retried
+*/
+func (a *Attachments) Add(name string, r io.Reader) *Attachment {
+	return a.add(name, &Attachment{reader: r})
+}
+
+/*
+AddBytes registers data under the given file name and returns a handle to it. See Add for how the name is chosen.
+Every call to Files or Finalize reads data from the start, so the registry can be sent any number of times
+*/
+func (a *Attachments) AddBytes(name string, data []byte) *Attachment {
+	return a.add(name, &Attachment{data: data})
+}
+
+// add registers res under a unique, referenceable version of name and returns it
+func (a *Attachments) add(name string, res *Attachment) *Attachment {
+	res.name = a.uniqueName(sanitizeAttachmentName(name))
+	a.names[res.name] = true
+	a.files = append(a.files, res)
+	return res
+}
+
+/*
+addImage checks that data is an image discord renders and registers it under the given file name, adding the
+extension of the detected format if name has none. The image's dimensions are returned along with the handle. If the
+image is not usable nothing is registered and an error for the property at path is returned instead
+*/
+func (a *Attachments) addImage(
+	path string, name string, data []byte,
+) (*Attachment, validation.ImageInfo, *validation.Error) {
+	info, err := validation.DetectImage(path, data)
+	if err != nil {
+		return nil, info, err
+	}
+	if filepath.Ext(name) == `` {
+		name += `.` + info.Format
+	}
+	return a.AddBytes(name, data), info, nil
+}
+
+/*
+Len returns the number of files in the registry
+*/
+func (a *Attachments) Len() int {
+	return len(a.files)
+}
+
+/*
+Files returns the registered files in the form disgord uploads them, in the order they were added. Files added with
+AddBytes get a new reader on every call, but files added with Add share the reader they were added with
+*/
+func (a *Attachments) Files() []disgord.CreateMessageFileParams {
+	var res []disgord.CreateMessageFileParams
+	for _, f := range a.files {
+		reader := f.reader
+		if reader == nil {
+			reader = bytes.NewReader(f.data)
+		}
+		res = append(res, disgord.CreateMessageFileParams{Reader: reader, FileName: f.name})
+	}
+	return res
+}
+
+/*
+Check compares the registry against the `attachment://` urls in embeds. References to files that are not registered
+are reported as errors at `embeds[i]`, and registered files that no embed references are reported as warnings at
+`attachments[i]`
+*/
+func (a *Attachments) Check(embeds ...*disgord.Embed) *[]error {
+	var errs []error
+	used := map[string]bool{}
+	for i, embed := range embeds {
+		for _, ref := range validation.AttachmentRefs(embed) {
+			used[ref.Name()] = true
+			if !a.names[ref.Name()] {
+				path := fmt.Sprintf(`embeds[%d].%v`, i, ref.Path)
+				errs = append(errs, validation.NewError(path, validation.ErrMissingAttachment, nil, ref.URL))
+			}
+		}
+	}
+	for i, f := range a.files {
+		if !used[f.name] {
+			path := fmt.Sprintf(`attachments[%d]`, i)
+			errs = append(errs, validation.NewWarning(path, validation.ErrUnusedAttachment, nil, f.name))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &errs
+}
+
+/*
+Finalize returns the registered files in the form disgord uploads them along with the problems Check finds with
+embeds
+*/
+func (a *Attachments) Finalize(embeds ...*disgord.Embed) ([]disgord.CreateMessageFileParams, *[]error) {
+	return a.Files(), a.Check(embeds...)
+}
+
+/*
+uniqueName returns name, or name with a number added before its extension if name is already registered
+*/
+func (a *Attachments) uniqueName(name string) string {
+	if !a.names[name] {
+		return name
+	}
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		if candidate := fmt.Sprintf(`%v_%d%v`, base, i, ext); !a.names[candidate] {
+			return candidate
+		}
+	}
+}
+
+// defaultAttachmentExt is the extension given to attachment names that do not have one
+const defaultAttachmentExt = `bin`
+
+/*
+sanitizeAttachmentName replaces the characters discord will not match in an `attachment://` url with `_`, and makes sure
+the name has an extension, as `attachment://` urls must name one (see validation.CheckValidAttachmentName). Names
+without one have `.bin` added, and an empty name becomes `file.bin`
+*/
+func sanitizeAttachmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if validation.CheckValidAttachmentName(name) {
+		return name
+	}
+
+	// Keep an extension that only needs the dots before it tidied, such as in `.png` or `graph..png`
+	base, ext := name, defaultAttachmentExt
+	if dot := strings.LastIndex(name, `.`); dot >= 0 && validation.CheckValidAttachmentName(`file`+name[dot:]) {
+		base, ext = name[:dot], name[dot+1:]
+	}
+	if base = strings.TrimRight(base, `.`); base == `` {
+		base = `file`
+	}
+	return base + `.` + ext
+}
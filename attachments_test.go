@@ -0,0 +1,157 @@
+package disgobed
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestAttachments tests the attachment registry names files, builds urls and reports unused and missing files
+*/
+func TestAttachments(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test names are made unique and referenceable`)
+	files := NewAttachments()
+	graph := files.AddBytes(`graph.png`, []byte(`one`))
+	t.Cmp(graph.Name(), `graph.png`)
+	t.Cmp(graph.URL(), `attachment://graph.png`)
+	t.Cmp(files.Add(`graph.png`, strings.NewReader(`two`)).Name(), `graph_1.png`)
+	t.Cmp(files.AddBytes(`graph.png`, nil).Name(), `graph_2.png`)
+	t.Cmp(files.AddBytes(`my graph (final).png`, nil).Name(), `my_graph__final_.png`)
+	t.Cmp(files.AddBytes(``, nil).Name(), `file.bin`)
+	t.Cmp(files.Len(), 5)
+
+	for name, want := range map[string]string{
+		`graph`:        `graph.bin`,
+		`.png`:         `file.png`,
+		`graph..png`:   `graph.png`,
+		`graph.`:       `graph.bin`,
+		`graph.tar-gz`: `graph.tar-gz.bin`,
+	} {
+		t.Cmp(NewAttachments().AddBytes(name, nil).Name(), want, `attachment added as %q`, name)
+	}
+
+	t.Log(`2. test handles set urls on the image setters`)
+	embed, errs := NewEmbed().
+		SetImage(NewImage().SetAttachment(graph)).
+		SetThumbnail(NewThumbnail().SetAttachment(graph)).
+		SetAuthor(NewAuthor().SetName(`me`).SetIconAttachment(graph)).
+		SetFooter(NewFooter().SetText(`footer`).SetIconAttachment(nil)).
+		Finalize()
+	t.Cmp(errs, &[]error{
+		validation.NewError(`footer.icon_url`, validation.ErrMissingAttachment, nil, validation.AttachmentURLPrefix),
+	})
+	t.Cmp(embed.Image.URL, graph.URL())
+	t.Cmp(embed.Thumbnail.URL, graph.URL())
+	t.Cmp(embed.Author.IconURL, graph.URL())
+
+	for _, name := range []string{``, `graph`, `my graph`, `.png`} {
+		sanitized := NewAttachments().AddBytes(name, nil)
+		image, errs := NewImage().SetAttachment(sanitized).Finalize()
+		t.Cmp(errs, td.Nil(), `attachment added as %q`, name)
+		t.Cmp(image.URL, sanitized.URL())
+	}
+
+	t.Log(`3. test unused and missing attachments are reported`)
+	embed.Footer.IconURL = `attachment://missing.png`
+	gotFiles, gotErrors := files.Finalize(embed)
+	t.Cmp(len(gotFiles), 5)
+	t.Cmp(gotFiles[1].FileName, `graph_1.png`)
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`embeds[0].footer.icon_url`, validation.ErrMissingAttachment, nil, `attachment://missing.png`),
+		validation.NewWarning(`attachments[1]`, validation.ErrUnusedAttachment, nil, `graph_1.png`),
+		validation.NewWarning(`attachments[2]`, validation.ErrUnusedAttachment, nil, `graph_2.png`),
+		validation.NewWarning(`attachments[3]`, validation.ErrUnusedAttachment, nil, `my_graph__final_.png`),
+		validation.NewWarning(`attachments[4]`, validation.ErrUnusedAttachment, nil, `file.bin`),
+	})
+
+	t.Log(`4. test messages upload registered files`)
+	files = NewAttachments()
+	logo := files.AddBytes(`logo.png`, []byte(`logo`))
+	params, gotErrors := NewMessage().
+		SetAttachments(files).
+		AddEmbed(NewEmbed().SetThumbnail(NewThumbnail().SetAttachment(logo))).
+		Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(params.Files, td.Len(1))
+	t.Cmp(params.Files[0].FileName, `logo.png`)
+	data, _ := ioutil.ReadAll(params.Files[0].Reader)
+	t.Cmp(string(data), `logo`)
+
+	_, gotErrors = NewMessage().
+		FailOn(validation.SeverityWarning).
+		SetAttachments(files).
+		AddRawEmbed(&disgord.Embed{Title: `no images`}).
+		Finalize()
+	t.Cmp(gotErrors, &[]error{validation.NewWarning(`attachments[0]`, validation.ErrUnusedAttachment, nil, `logo.png`)})
+
+	t.Log(`5. test files added as bytes can be sent more than once`)
+	for i := 0; i < 2; i++ {
+		sent := files.Files()
+		t.Cmp(sent, td.Len(1))
+		data, _ = ioutil.ReadAll(sent[0].Reader)
+		t.Cmp(string(data), `logo`, `send %d`, i)
+	}
+	stream := NewAttachments()
+	stream.Add(`log.txt`, strings.NewReader(`log`))
+	data, _ = ioutil.ReadAll(stream.Files()[0].Reader)
+	t.Cmp(string(data), `log`)
+	data, _ = ioutil.ReadAll(stream.Files()[0].Reader)
+	t.Cmp(string(data), ``) // Readers added with Add can only be read once
+
+	t.Log(`6. test files cannot share a name with the registry's files`)
+	files = NewAttachments()
+	chart := files.AddBytes(`graph.png`, []byte(`registry`))
+	params, gotErrors = NewMessage().
+		AddFile(`graph.png`, strings.NewReader(`file`)).
+		AddFile(`graph.png`, strings.NewReader(`again`)).
+		SetAttachments(files).
+		AddEmbed(NewEmbed().SetImage(NewImage().SetAttachment(chart))).
+		Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`files[1].name`, validation.ErrDuplicateFileName, nil, `graph.png`),
+		validation.NewError(`attachments[0]`, validation.ErrDuplicateFileName, nil, `graph.png`),
+	})
+	t.Cmp(params.Files, td.Len(1))
+	data, _ = ioutil.ReadAll(params.Files[0].Reader)
+	t.Cmp(string(data), `file`)
+}
+
+/*
+TestImageFromBytes tests images and thumbnails can be created from image data
+*/
+func TestImageFromBytes(tt *testing.T) {
+	t := td.NewT(tt)
+
+	var data bytes.Buffer
+	t.CmpNoError(png.Encode(&data, image.NewGray(image.Rect(0, 0, 64, 32))))
+
+	t.Log(`1. test dimensions are detected and the image is registered`)
+	files := NewAttachments()
+	img, errs := NewImageFromBytes(files, `chart`, data.Bytes()).Finalize()
+	t.Cmp(errs, td.Nil())
+	t.Cmp(img, &disgord.EmbedImage{URL: `attachment://chart.png`, Width: 64, Height: 32})
+
+	thumb, errs := NewThumbnailFromReader(files, `chart.png`, bytes.NewReader(data.Bytes())).Finalize()
+	t.Cmp(errs, td.Nil())
+	t.Cmp(thumb, &disgord.EmbedThumbnail{URL: `attachment://chart_1.png`, Width: 64, Height: 32})
+	t.Cmp(files.Len(), 2)
+
+	t.Log(`2. test bad images are rejected`)
+	_, errs = NewEmbed().SetImage(NewImageFromBytes(files, `chart.bmp`, []byte(`BM`))).Finalize()
+	t.Cmp(errs, &[]error{validation.NewError(`image.url`, validation.ErrUnsupportedImage, nil, nil)})
+	_, errs = NewThumbnailFromBytes(files, `chart.png`, data.Bytes()[:20]).Finalize()
+	t.Cmp(errs, &[]error{validation.NewError(`url`, validation.ErrCorruptImage, nil, validation.PNGFormat)})
+	_, errs = NewImageFromBytes(nil, `chart.png`, data.Bytes()).Finalize()
+	t.Cmp(errs, &[]error{validation.NewError(`url`, validation.ErrMissingAttachment, nil, `chart.png`)})
+	t.Cmp(files.Len(), 2)
+}
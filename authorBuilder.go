@@ -1,8 +1,6 @@
 package disgobed
 
 import (
-	"fmt"
-
 	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
 )
@@ -13,26 +11,102 @@ AuthorBuilder wraps the discordgo.MessageEmbedAuthor type and adds features
 type AuthorBuilder struct {
 	*disgord.EmbedAuthor
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before an author is
 sent. Finalize will also purge the error cache!
+Warnings are dropped, so a nil result means the author has no problems discord will reject
 */
 func (a *AuthorBuilder) Finalize() (*disgord.EmbedAuthor, *[]error) {
 	defer func(a *AuthorBuilder) { a.Errors = nil }(a)
+	return a.EmbedAuthor, validation.FilterSeverity(a.Errors, a.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a AuthorBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (a *AuthorBuilder) peek() (*disgord.EmbedAuthor, *[]error) {
 	return a.EmbedAuthor, a.Errors
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in AuthorBuilder. If the pointer is nil a new error slice
-is created. This function takes the same inputs as fmt.Sprintf
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) Strict() *AuthorBuilder {
+	a.opts.strict = true
+	return a
+}
+
+/*
+SetLimits makes the AuthorBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) SetLimits(limits validation.Limits) *AuthorBuilder {
+	a.opts.profile = &limits
+	return a
+}
+
+/*
+addError records a validation error for the property at path in the error slice stored in AuthorBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
+*/
+func (a *AuthorBuilder) addError(path string, code error, limit interface{}, value interface{}) {
+	if a.Errors == nil {
+		a.Errors = &[]error{}
+	}
+	err := validation.NewError(path, code, limit, value)
+	a.opts.check(err)
+	*a.Errors = append(*a.Errors, err)
+}
+
+/*
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
 */
-func (a *AuthorBuilder) addError(format string, values ...interface{}) {
+func (a *AuthorBuilder) addRawError(err error) {
+	a.opts.check(err)
 	if a.Errors == nil {
 		a.Errors = &[]error{}
 	}
-	*a.Errors = append(*a.Errors, fmt.Errorf(format, values...))
+	*a.Errors = append(*a.Errors, err)
+}
+
+/*
+fit applies the AuthorBuilder's truncation policy to the value of the property at path, recording a warning if the value was
+cut to fit limit
+*/
+func (a *AuthorBuilder) fit(path string, value string, limit int) string {
+	value, warning := a.opts.fit(path, value, limit)
+	if warning != nil {
+		a.addRawError(warning)
+	}
+	return value
+}
+
+/*
+EnableTruncation makes the AuthorBuilder cut values that are too long down to their limit, appending ellipsis to mark the cut,
+instead of dropping them. Each cut is recorded as a warning. Pass validation.DefaultEllipsis for the standard marker.
+It then returns the pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) EnableTruncation(ellipsis string) *AuthorBuilder {
+	a.opts.truncate = true
+	a.opts.ellipsis = ellipsis
+	return a
+}
+
+/*
+DisableTruncation restores the default behaviour of dropping values that are too long, then returns the pointer to the
+AuthorBuilder
+*/
+func (a *AuthorBuilder) DisableTruncation() *AuthorBuilder {
+	a.opts.truncate = false
+	return a
 }
 
 /*
@@ -47,53 +121,121 @@ func NewAuthor() *AuthorBuilder {
 }
 
 /*
-SetURL sets the author field link to the value given, then returns the pointer to the AuthorBuilder
+SetURL sets the author field link to the value given, then returns the pointer to the AuthorBuilder. The link must be a
+valid http:// or https:// url (see validation.ValidateURL), otherwise it will not be added
+http:// urls are accepted but recorded as a warning
+(This function fails silently)
 */
 func (a *AuthorBuilder) SetURL(url string) *AuthorBuilder {
-	a.URL = url
+	if err := validation.ValidateURL(`url`, url, false, a.opts.limits()); err == nil {
+		a.URL = url
+		if warning := validation.LintURL(`url`, url); warning != nil {
+			a.addRawError(warning)
+		}
+	} else {
+		a.addRawError(err)
+	}
 	return a
 }
 
 /*
 SetIconURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the AuthorBuilder (if
-the string does not start with one of these, no URL will be added). It then returns the pointer to the AuthorBuilder structure
+the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the AuthorBuilder structure
+http:// urls are accepted but recorded as a warning
 (This function fails silently)
 */
 func (a *AuthorBuilder) SetIconURL(iconUrl string) *AuthorBuilder {
-	if validation.CheckValidIconURL(iconUrl) {
+	if err := validation.ValidateURL(`icon_url`, iconUrl, true, a.opts.limits()); err == nil {
 		a.IconURL = iconUrl
+		if warning := validation.LintURL(`icon_url`, iconUrl); warning != nil {
+			a.addRawError(warning)
+		}
 	} else {
-		a.addError(validation.InvalidUrlErrTemplateString, `author iconUrl`, iconUrl)
+		a.addRawError(err)
 	}
 	return a
 }
 
+/*
+SetIconAttachment sets the author icon url to the `attachment://` url of the given attachment, then returns the pointer to the
+AuthorBuilder. The attachment must be uploaded with the message, such as by passing its registry to
+MessageBuilder.SetAttachments. A nil attachment is not added
+(This function fails silently)
+*/
+func (a *AuthorBuilder) SetIconAttachment(attachment *Attachment) *AuthorBuilder {
+	if attachment == nil {
+		a.addError(`icon_url`, validation.ErrMissingAttachment, nil, validation.AttachmentURLPrefix)
+		return a
+	}
+	return a.SetIconURL(attachment.URL())
+}
+
 /*
 SetName takes a string and sets the AuthorBuilder's name to that value. It then returns the pointer to the AuthorBuilder. The discord
-API limits AuthorBuilder names to 256 characters, so this function will do nothing if len(name) > 256
+API limits AuthorBuilder names to 256 characters, so this function will do nothing if validation.CharCount(name) > 256.
+If truncation is enabled, names that are too long are cut down instead
 (This function fails silently)
 */
 func (a *AuthorBuilder) SetName(name string) *AuthorBuilder {
-	var limit = 256
-	if len(name) <= limit {
+	limit := a.opts.limits().AuthorName
+	name = a.fit(`name`, name, limit)
+	if length := validation.CharCount(name); length <= limit {
 		a.Name = name
 	} else {
-		a.addError(validation.CharacterCountExceedsLimitErrTemplateString, `author name`, limit, len(name), name)
+		a.addError(`name`, validation.ErrTooLong, limit, length)
 	}
 	return a
 }
 
 /*
 SetProxyIconURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the AuthorBuilder
-(if the string does not start with one of these, no URL will be added). It then returns the pointer to the AuthorBuilder
+(if the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the AuthorBuilder
 structure
+Discord ignores proxy urls on input, so setting one is recorded as a warning. http:// urls are also recorded as
+warnings
 (This function fails silently)
 */
 func (a *AuthorBuilder) SetProxyIconURL(proxyIconUrl string) *AuthorBuilder {
-	if validation.CheckValidIconURL(proxyIconUrl) {
+	if err := validation.ValidateURL(`proxy_icon_url`, proxyIconUrl, true, a.opts.limits()); err == nil {
 		a.ProxyIconURL = proxyIconUrl
+		if warning := validation.LintURL(`proxy_icon_url`, proxyIconUrl); warning != nil {
+			a.addRawError(warning)
+		}
+		a.addRawError(validation.NewWarning(`proxy_icon_url`, validation.ErrIgnoredProperty, nil, proxyIconUrl))
 	} else {
-		a.addError(validation.InvalidUrlErrTemplateString, `author proxyIconUrl`, proxyIconUrl)
+		a.addRawError(err)
 	}
 	return a
 }
+
+/*
+ClearName removes the author's name, then returns the pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) ClearName() *AuthorBuilder {
+	a.Name = ``
+	return a
+}
+
+/*
+ClearURL removes the author's link, then returns the pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) ClearURL() *AuthorBuilder {
+	a.URL = ``
+	return a
+}
+
+/*
+ClearIconURL removes the author's icon url, then returns the pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) ClearIconURL() *AuthorBuilder {
+	a.IconURL = ``
+	return a
+}
+
+/*
+ClearProxyIconURL removes the author's proxy icon url, then returns the pointer to the AuthorBuilder
+*/
+func (a *AuthorBuilder) ClearProxyIconURL() *AuthorBuilder {
+	a.ProxyIconURL = ``
+	return a
+}
@@ -1,7 +1,6 @@
 package disgobed
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/Nightmarlin/disgobed/validation"
@@ -88,7 +87,7 @@ func TestAuthor_Finalize(tt *testing.T) {
 	t.Log(` - create iconUrl, expected author and expected errors`)
 	testUrl = `aka.ms/ps7`
 	wantErrors = &[]error{
-		fmt.Errorf(validation.InvalidUrlErrTemplateString, "author iconUrl", testUrl),
+		validation.NewError(`icon_url`, validation.ErrInvalidURL, nil, testUrl),
 	}
 	wantAuthor = &disgord.EmbedAuthor{
 		URL:          "",
@@ -1,11 +1,12 @@
 /*
 Package disgobed wraps the discordgo embed with helper functions to facilitate easier construction.
 Note that all methods in this module act ByReference, directly changing the embed they are called on, instead of
-creating and returning a new embed
+creating and returning a new embed. EmbedBuilder.Immutable switches an embed to returning changed copies instead
 */
 package disgobed
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,12 @@ import (
 	"github.com/andersfylling/disgord"
 )
 
+/*
+ZeroWidthFieldName is a field name that displays as nothing, for use as the name of continuation fields. Discord does
+not allow field names to be empty
+*/
+const ZeroWidthFieldName = "\u200b"
+
 /*
 EmbedBuilder wraps the disgord.EmbedBuilder type and adds features. Never create it directly, instead use the NewEmbed function
 
@@ -23,55 +30,184 @@ and call the methods to set the properties, allowing for chains that look like t
 	embed := NewEmbed()
 		.SetTitle(`example`)
 		.SetDescription(`test`)
-		.SetURL(`example.com`)
+		.SetURL(`https://example.com`)
 		.Finalize()
 
 for healthy embedment!
+
+The character limits quoted by the setters are those of validation.DefaultLimits. Use SetLimits to check against a
+different profile, such as validation.CurrentLimits
 */
 type EmbedBuilder struct {
 	*disgord.Embed
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Validate returns whether or not discord is likely accept the embed attached to it. If discord is unlikely to
 accept the embed, it returns a list of reasons why. If msg is not nil, the checker will also validate `attachment://`
-urls message
+urls message.
+Only problems at least as severe as the FailOn threshold are returned, so by default warnings are dropped and a nil
+result means discord should accept the embed. The embed is checked by validation.DefaultRules (or the rules given to
+SetRules) followed by any extra rules given for this call only
 */
-func (e *EmbedBuilder) Validate(msg *disgord.Message) *[]error {
-	toCheck, errs := e.Finalize()
-	if errs != nil { // Make use of builtin err checking
-		return errs // Short-Circuit and dont run expensive checks if we already have errors
+func (e *EmbedBuilder) Validate(msg *disgord.Message, extra ...validation.Rule) *[]error {
+	toCheck, errs := e.finalize()
+	if validation.HasErrors(errs) { // Make use of builtin err checking
+		return validation.FilterSeverity(errs, e.opts.failOn) // Short-Circuit and dont run expensive checks if we already have errors
 	}
-	return validation.ValidateEmbed(toCheck, msg)
+	found := e.opts.ruleSet().Validate(toCheck, msg, e.opts.limits(), extra...)
+	errs = validation.MergeErrors(errs, withoutRecorded(errs, found))
+	return validation.FilterSeverity(errs, e.opts.failOn)
+}
+
+/*
+FailOn sets the least severe kind of problem that makes Finalize and Validate fail. The default, validation.SeverityError, only
+reports problems discord will reject, while validation.SeverityWarning also reports bad practices such as http:// urls,
+which is useful in CI. It then returns the pointer to the EmbedBuilder
+*/
+func (e *EmbedBuilder) FailOn(threshold validation.Severity) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.FailOn(threshold) })
+	}
+	e.opts.failOn = threshold
+	return e
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before an embed is
 sent. Finalize will also purge the error cache!
+Only problems at least as severe as the FailOn threshold are returned, so by default warnings are dropped and a nil
+result means discord should accept the embed. In immutable mode a copy of the embed and errors is returned instead, and
+the error cache is left alone
 */
 func (e *EmbedBuilder) Finalize() (*disgord.Embed, *[]error) {
+	embed, errs := e.finalize()
+	return embed, validation.FilterSeverity(errs, e.opts.failOn)
+}
+
+/*
+finalize works like Finalize, but returns every recorded problem whatever its severity, for callers that filter them
+with their own threshold
+*/
+func (e *EmbedBuilder) finalize() (*disgord.Embed, *[]error) {
+	if e.opts.immutable {
+		res := e.Clone()
+		return res.Embed, res.Errors
+	}
 	defer func(e *EmbedBuilder) { e.Errors = nil }(e)
 	return e.Embed, e.Errors
 }
 
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the EmbedBuilder
+*/
+func (e *EmbedBuilder) Strict() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.Strict() })
+	}
+	e.opts.strict = true
+	return e
+}
+
+/*
+SetLimits makes the EmbedBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the EmbedBuilder
+*/
+func (e *EmbedBuilder) SetLimits(limits validation.Limits) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetLimits(limits) })
+	}
+	e.opts.profile = &limits
+	return e
+}
+
+/*
+SetRules makes Validate check the embed against the given rules instead of validation.DefaultRules, then returns the
+pointer to the EmbedBuilder. Use validation.DefaultRules.Clone() as a starting point to add or disable rules for this
+embed only
+*/
+func (e *EmbedBuilder) SetRules(rules *validation.RuleSet) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRules(rules) })
+	}
+	e.opts.rules = rules
+	return e
+}
+
+/*
+Clone returns a deep copy of the EmbedBuilder, including its fields, author, footer and other properties, its recorded
+errors and its settings. Changing the copy leaves the original unchanged, and the other way around
+*/
+func (e *EmbedBuilder) Clone() *EmbedBuilder {
+	res := &EmbedBuilder{
+		Embed: copyEmbed(e.Embed),
+		opts:  e.opts,
+	}
+	if e.Errors != nil {
+		errs := append([]error(nil), *e.Errors...)
+		res.Errors = &errs
+	}
+	return res
+}
+
+/*
+Immutable enables immutable mode, in which every setter leaves the EmbedBuilder unchanged and returns a changed copy
+instead, and Finalize leaves the error cache alone. This makes a shared base embed safe to build on from many goroutines
+
+	base := NewEmbed().SetColor(0x00ff00).SetFooter(NewFooter().SetText(`bot`)).Immutable()
+	embed, errs := base.SetTitle(`pong`).Finalize() // base still has no title
+
+Copies stay in immutable mode. The embedded disgord.Embed can still be changed directly, which immutable mode does not
+prevent. It then returns the pointer to the EmbedBuilder
+*/
+func (e *EmbedBuilder) Immutable() *EmbedBuilder {
+	if e.opts.immutable { // Avoid writing to a builder that may already be shared
+		return e
+	}
+	e.opts.immutable = true
+	return e
+}
+
+/*
+onCopy makes change to a copy of the EmbedBuilder and returns the copy. It is used by the setters in immutable mode, and
+lets change call other setters without each of them making a copy of its own
+*/
+func (e *EmbedBuilder) onCopy(change func(c *EmbedBuilder)) *EmbedBuilder {
+	res := e.Clone()
+	res.opts.immutable = false
+	change(res)
+	res.opts.immutable = true
+	return res
+}
+
 /*
 Generate strips aways the extra functions and returns the wrapped type without the cached validation errors. Allows for
-immediate addition to a message. This method does not purge the error cache
+immediate addition to a message. This method does not purge the error cache. In immutable mode a copy of the embed is
+returned
 */
 func (e *EmbedBuilder) Generate() *disgord.Embed {
+	if e.opts.immutable {
+		return copyEmbed(e.Embed)
+	}
 	return e.Embed
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in EmbedBuilder. If the pointer is nil a new error slice
-is created. This function takes the same inputs as fmt.Sprintf
+addError records a validation error for the property at path in the error slice stored in EmbedBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
 */
-func (e *EmbedBuilder) addError(format string, values ...interface{}) {
+func (e *EmbedBuilder) addError(path string, code error, limit interface{}, value interface{}) {
 	if e.Errors == nil {
 		e.Errors = &[]error{}
 	}
-	*e.Errors = append(*e.Errors, fmt.Errorf(format, values...))
+	err := validation.NewError(path, code, limit, value)
+	e.opts.check(err)
+	*e.Errors = append(*e.Errors, err)
 }
 
 /*
@@ -79,6 +215,7 @@ addRawError takes a pre-existing error and adds it to the stored slice. If the p
 created.
 */
 func (e *EmbedBuilder) addRawError(err error) {
+	e.opts.check(err)
 	if e.Errors == nil {
 		e.Errors = &[]error{}
 	}
@@ -86,10 +223,11 @@ func (e *EmbedBuilder) addRawError(err error) {
 }
 
 /*
-addAllRawErrors takes a pre-existing error slice and adds it to the stored slice. If the pointer is nil a new error
-slice is created.
+addAllRawErrors takes a pre-existing error slice from a sub-builder and adds it to the stored slice, placing the paths
+of any validation errors beneath prefix. If the pointer is nil a new error slice is created.
 */
-func (e *EmbedBuilder) addAllRawErrors(errs *[]error) {
+func (e *EmbedBuilder) addAllRawErrors(prefix string, errs *[]error) {
+	errs = validation.PrefixErrors(prefix, errs)
 	if errs == nil {
 		return
 	}
@@ -109,39 +247,203 @@ func NewEmbed() *EmbedBuilder {
 	return res
 }
 
+/*
+Used returns the number of characters the embed currently counts towards its total character limit. This is the sum
+of the title, description, field names and values, footer text and author name
+*/
+func (e *EmbedBuilder) Used() int {
+	return validation.TotalCharacterCount(e.Embed)
+}
+
+/*
+Remaining returns the number of characters that can still be added to the embed before its total character limit is
+reached
+*/
+func (e *EmbedBuilder) Remaining() int {
+	return e.opts.limits().Total - e.Used()
+}
+
+/*
+fit applies the embed's truncation policy to the value of the property at path, recording a warning if the value was
+cut. Values are cut to whichever is smaller of limit and the space the current value leaves in the total character
+budget
+*/
+func (e *EmbedBuilder) fit(path string, value string, limit int, current string) string {
+	if available := e.Remaining() + validation.CharCount(current); available < limit {
+		limit = available
+	}
+	value, warning := e.opts.fit(path, value, limit)
+	if warning != nil {
+		e.addRawError(warning)
+	}
+	return value
+}
+
+/*
+EnableTruncation makes the embed cut values that are too long down to their limit, or to the space left in the total
+character budget, appending ellipsis to mark the cut instead of dropping them. Each cut is recorded as a warning. Pass
+validation.DefaultEllipsis for the standard marker. It then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) EnableTruncation(ellipsis string) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.EnableTruncation(ellipsis) })
+	}
+	e.opts.truncate = true
+	e.opts.ellipsis = ellipsis
+	return e
+}
+
+/*
+DisableTruncation restores the default behaviour of dropping values that are too long, then returns the pointer to the
+embed
+*/
+func (e *EmbedBuilder) DisableTruncation() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.DisableTruncation() })
+	}
+	e.opts.truncate = false
+	return e
+}
+
+/*
+fitsBudget checks whether replacing a counted property of oldLen characters with one of newLen characters keeps the
+embed within its total character limit. If it would not, an error is recorded against path
+*/
+func (e *EmbedBuilder) fitsBudget(path string, oldLen int, newLen int) bool {
+	limit := e.opts.limits().Total
+	if total := e.Used() - oldLen + newLen; total > limit {
+		e.addError(path, validation.ErrTotalTooLong, limit, total)
+		return false
+	}
+	return true
+}
+
+/*
+NewField creates and returns an empty field that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewField() *FieldBuilder {
+	res := NewField()
+	res.opts = e.opts
+	return res
+}
+
+/*
+NewAuthor creates and returns an empty author that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewAuthor() *AuthorBuilder {
+	res := NewAuthor()
+	res.opts = e.opts
+	return res
+}
+
+/*
+NewFooter creates and returns an empty footer that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewFooter() *FooterBuilder {
+	res := NewFooter()
+	res.opts = e.opts
+	return res
+}
+
+/*
+NewImage creates and returns an empty image that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewImage() *ImageBuilder {
+	res := NewImage()
+	res.opts = e.opts
+	return res
+}
+
+/*
+NewThumbnail creates and returns an empty thumbnail that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewThumbnail() *ThumbnailBuilder {
+	res := NewThumbnail()
+	res.opts = e.opts
+	return res
+}
+
+/*
+NewVideo creates and returns an empty video that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewVideo() *VideoBuilder {
+	res := NewVideo()
+	res.opts = e.opts
+	return res
+}
+
+/*
+NewProvider creates and returns an empty provider that inherits the embed's strict mode, truncation and limits settings
+*/
+func (e *EmbedBuilder) NewProvider() *ProviderBuilder {
+	res := NewProvider()
+	res.opts = e.opts
+	return res
+}
+
 /*
 SetTitle edits the embed's title and returns the pointer to the embed. The discord API limits embed titles to 256
-characters, so this function will do nothing if len(title) > 256
+characters, so this function will do nothing if validation.CharCount(title) > 256 or if the new title would push the
+embed over its total character limit. If truncation is enabled, titles that are too long are cut down instead
 (This function fails silently)
 */
 func (e *EmbedBuilder) SetTitle(title string) *EmbedBuilder {
-	if len(title) <= validation.LowerCharLimit {
-		e.Title = title
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetTitle(title) })
+	}
+	limit := e.opts.limits().Title
+	title = e.fit(`title`, title, limit, e.Title)
+	if length := validation.CharCount(title); length <= limit {
+		if e.fitsBudget(`title`, validation.CharCount(e.Title), length) {
+			e.Title = title
+		}
 	} else {
-		e.addError(validation.CharacterCountExceedsLimitErrTemplateString, `embed title`, validation.LowerCharLimit, len(title), title)
+		e.addError(`title`, validation.ErrTooLong, limit, length)
 	}
 	return e
 }
 
 /*
 SetDescription edits the embed's description and returns the pointer to the embed. The discord API limits embed
-descriptions to 2048 characters, so this function will do nothing if len(desc) > 2048
+descriptions to 2048 characters, so this function will do nothing if validation.CharCount(desc) > 2048 or if the new
+description would push the embed over its total character limit. If truncation is enabled, descriptions that are too
+long are cut down instead
 (This function fails silently)
 */
 func (e *EmbedBuilder) SetDescription(desc string) *EmbedBuilder {
-	if len(desc) <= validation.UpperCharLimit {
-		e.Description = desc
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetDescription(desc) })
+	}
+	limit := e.opts.limits().Description
+	desc = e.fit(`description`, desc, limit, e.Description)
+	if length := validation.CharCount(desc); length <= limit {
+		if e.fitsBudget(`description`, validation.CharCount(e.Description), length) {
+			e.Description = desc
+		}
 	} else {
-		e.addError(validation.CharacterCountExceedsLimitLongErrTemplateString, `embed description`, validation.UpperCharLimit, len(desc))
+		e.addError(`description`, validation.ErrTooLong, limit, length)
 	}
 	return e
 }
 
 /*
-SetURL edits the embed's main URL and returns the pointer to the embed
+SetURL edits the embed's main URL and returns the pointer to the embed. The URL must be a valid http:// or https:// url
+(see validation.ValidateURL), otherwise it will not be added
+http:// urls are accepted but recorded as a warning
+(This function fails silently)
 */
 func (e *EmbedBuilder) SetURL(url string) *EmbedBuilder {
-	e.URL = url
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetURL(url) })
+	}
+	if err := validation.ValidateURL(`url`, url, false, e.opts.limits()); err == nil {
+		e.URL = url
+		if warning := validation.LintURL(`url`, url); warning != nil {
+			e.addRawError(warning)
+		}
+	} else {
+		e.addRawError(err)
+	}
 	return e
 }
 
@@ -151,10 +453,14 @@ Color values must be between 0 and 16777215 otherwise the change will not be reg
 (This function fails silently)
 */
 func (e *EmbedBuilder) SetColor(color int) *EmbedBuilder {
-	if color >= 0 && color < validation.MaxColorValue {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetColor(color) })
+	}
+	limit := e.opts.limits().Color
+	if color >= 0 && color <= limit {
 		e.Color = color
 	} else {
-		e.addError(validation.ValueNotBetweenErrTemplateString, `embed color`, color, 0, validation.MaxColorValue)
+		e.addError(`color`, validation.ErrOutOfRange, limit, color)
 	}
 	return e
 }
@@ -164,6 +470,9 @@ SetCurrentTimestamp sets the embed's timestamp to the current UTC time in the ap
 the pointer to the embed
 */
 func (e *EmbedBuilder) SetCurrentTimestamp() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetCurrentTimestamp() })
+	}
 	utcTime := disgord.Time{Time: time.Now().UTC()}
 	return e.setRawTimestamp(utcTime)
 }
@@ -174,6 +483,9 @@ The value stored is the corresponding UTC time in the appropriate discord format
 SetCustomTimestamp returns the pointer to the embed
 */
 func (e *EmbedBuilder) SetCustomTimestamp(t time.Time) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetCustomTimestamp(t) })
+	}
 	utcTime := disgord.Time{Time: t.UTC()}
 	return e.setRawTimestamp(utcTime)
 }
@@ -191,6 +503,9 @@ func (e *EmbedBuilder) setRawTimestamp(timestamp disgord.Time) *EmbedBuilder {
 InlineAllFields sets the Inline property on all currently attached fields to true and returns the pointer to the embed
 */
 func (e *EmbedBuilder) InlineAllFields() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.InlineAllFields() })
+	}
 	for _, f := range e.Fields {
 		f.Inline = true
 	}
@@ -201,6 +516,9 @@ func (e *EmbedBuilder) InlineAllFields() *EmbedBuilder {
 OutlineAllFields sets the Inline property on all currently attached fields to false and returns the pointer to the embed
 */
 func (e *EmbedBuilder) OutlineAllFields() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.OutlineAllFields() })
+	}
 	for _, f := range e.Fields {
 		f.Inline = false
 	}
@@ -209,12 +527,15 @@ func (e *EmbedBuilder) OutlineAllFields() *EmbedBuilder {
 
 /*
 AddFields takes N FieldBuilder structures and adds them to the embed, then returns the pointer to the embed.
-Note that FieldBuilder structures are `Finalize`d once added and should not be changed after being added.
+Note that copies of the FieldBuilder structures are stored, so changing them afterwards does not change the embed.
 The discord API limits embeds to having 25 Fields, so this function will add the first items from the list until that
 limit is reached
 (This function fails silently)
 */
 func (e *EmbedBuilder) AddFields(fields ...*FieldBuilder) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.AddFields(fields...) })
+	}
 	for _, f := range fields {
 		e.AddField(f)
 	}
@@ -228,6 +549,9 @@ that limit is reached
 (This function fails silently)
 */
 func (e *EmbedBuilder) AddRawFields(fields ...*disgord.EmbedField) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.AddRawFields(fields...) })
+	}
 	for _, f := range fields {
 		e.AddRawField(f)
 	}
@@ -236,60 +560,134 @@ func (e *EmbedBuilder) AddRawFields(fields ...*disgord.EmbedField) *EmbedBuilder
 
 /*
 AddField takes a FieldBuilder structure and adds it to the embed, then returns the pointer to the embed.
-Note that the FieldBuilder structure is `Finalize`d once added and should not be changed after being added.
+Note that a copy of the FieldBuilder structure is stored, so changing it afterwards does not change the embed, and its
+errors are left in place, so it can be added to many embeds.
 The discord API limits embeds to having 25 Fields, so this function will not add any fields if the limit has already
 been reached. All errors are propagated to the main embed
 (This function fails silently)
 */
 func (e *EmbedBuilder) AddField(field *FieldBuilder) *EmbedBuilder {
-	res, errs := field.Finalize()
-	e.addAllRawErrors(errs)
-	return e.AddRawField(res)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.AddField(field) })
+	}
+	res, errs := field.peek()
+	e.addAllRawErrors(fmt.Sprintf(`fields[%d]`, len(e.Fields)), errs)
+	return e.AddRawField(res.DeepCopy().(*disgord.EmbedField))
 }
 
 /*
 AddRawField takes a disgord.EmbedField structure and adds it to the embed, then returns the pointer to the
 embed. The discord API limits embeds to having 25 Fields, so this function will not add any fields if the limit has
-already been reached
+already been reached, or if the field would push the embed over its total character limit
 (This function fails silently)
 */
 func (e *EmbedBuilder) AddRawField(field *disgord.EmbedField) *EmbedBuilder {
-	if len(e.Fields) < validation.MaxFieldCount {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.AddRawField(field) })
+	}
+	return e.addRawField(fmt.Sprintf(`fields[%d]`, len(e.Fields)), field)
+}
+
+/*
+addRawField works like AddRawField, but reports a field that does not fit the total character limit at path. This lets
+embeds read from a source report problems at the position the field had in the source
+*/
+func (e *EmbedBuilder) addRawField(path string, field *disgord.EmbedField) *EmbedBuilder {
+	if limit := e.opts.limits().FieldCount; len(e.Fields) >= limit {
+		e.addError(`fields`, validation.ErrTooMany, limit, len(e.Fields)+1)
+	} else if e.fitsBudget(path, 0, fieldCharCount(field)) {
 		e.Fields = append(e.Fields, field)
-	} else {
-		e.addError(validation.FieldLimitReachedErrTemplateString, field.Name, validation.MaxFieldCount)
+	}
+	return e
+}
+
+/*
+AddSplitField adds value to the embed as a field called name, spreading it across as many consecutive fields as it
+needs, then returns the pointer to the embed. Values are broken between lines wherever possible, and otherwise between
+characters without splitting combined characters such as emoji sequences. Every field after the first is called
+continuationName, such as `Name (cont.)` or ZeroWidthFieldName to leave it visually unnamed.
+The fields stay within the embed's field count and total character limits. If value does not fit, or name or
+continuationName is not a valid field name, as much as fits is added and a validation.ErrOverflow error records how
+many characters were left out
+(This function fails silently)
+*/
+func (e *EmbedBuilder) AddSplitField(name string, value string, continuationName string) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.AddSplitField(name, value, continuationName) })
+	}
+	limits := e.opts.limits()
+	if value == `` {
+		e.addError(fmt.Sprintf(`fields[%d].value`, len(e.Fields)), validation.ErrEmpty, nil, value)
+		return e
+	}
+
+	fieldName := name
+	for value != `` {
+		path := fmt.Sprintf(`fields[%d]`, len(e.Fields))
+		if err := fieldNameError(path+`.name`, fieldName, limits.FieldName); err != nil {
+			e.addRawError(err)
+			e.addError(path, validation.ErrOverflow, nil, validation.CharCount(value)) // Say how much was left out
+			break
+		}
+
+		available := e.Remaining() - validation.CharCount(fieldName)
+		if available > limits.FieldValue {
+			available = limits.FieldValue
+		}
+		if len(e.Fields) >= limits.FieldCount || available <= 0 {
+			e.addError(path, validation.ErrOverflow, nil, validation.CharCount(value))
+			break
+		}
+
+		var chunk string
+		chunk, value = splitValue(value, available)
+		e.Fields = append(e.Fields, &disgord.EmbedField{Name: fieldName, Value: chunk})
+		fieldName = continuationName
 	}
 	return e
 }
 
 /*
 SetAuthor takes an AuthorBuilder structure and sets the embed's author field to it, then returns the pointer to the embed.
-Note that the AuthorBuilder structure is `Finalize`d once added and should not be changed after being added. All errors are
-propagated to the main embed
+Note that a copy of the AuthorBuilder structure is stored, so changing it afterwards does not change the embed, and its
+errors are left in place, so it can be shared between embeds. All errors are propagated to the main embed
 */
 func (e *EmbedBuilder) SetAuthor(author *AuthorBuilder) *EmbedBuilder {
-	res, errs := author.Finalize()
-	e.addAllRawErrors(errs)
-	return e.SetRawAuthor(res)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetAuthor(author) })
+	}
+	res, errs := author.peek()
+	e.addAllRawErrors(`author`, errs)
+	return e.SetRawAuthor(res.DeepCopy().(*disgord.EmbedAuthor))
 }
 
 /*
 SetRawAuthor takes a disgord.EmbedAuthor and sets the embed's author field to it, then returns the pointer to
-the embed
+the embed. The author is not set if its name would push the embed over its total character limit
+(This function fails silently)
 */
 func (e *EmbedBuilder) SetRawAuthor(author *disgord.EmbedAuthor) *EmbedBuilder {
-	e.Author = author
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRawAuthor(author) })
+	}
+	if e.fitsBudget(`author.name`, authorCharCount(e.Author), authorCharCount(author)) {
+		e.Author = author
+	}
 	return e
 }
 
 /*
 SetThumbnail takes a ThumbnailBuilder structure and sets the embed's thumbnail field to it, then returns the pointer to the
-embed. Note that the ThumbnailBuilder structure is `Finalize`d once added and should not be changed after being added
+embed. Note that a copy of the ThumbnailBuilder structure is stored, so changing it afterwards does not change the
+embed, and its errors are left in place, so it can be shared between embeds. All errors are propagated to the main embed
 */
 func (e *EmbedBuilder) SetThumbnail(thumb *ThumbnailBuilder) *EmbedBuilder {
-	res, errs := thumb.Finalize()
-	e.addAllRawErrors(errs)
-	return e.SetRawThumbnail(res)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetThumbnail(thumb) })
+	}
+	res, errs := thumb.peek()
+	e.addAllRawErrors(`thumbnail`, errs)
+	return e.SetRawThumbnail(res.DeepCopy().(*disgord.EmbedThumbnail))
 }
 
 /*
@@ -297,20 +695,25 @@ SetRawThumbnail takes a disgord.EmbedThumbnail and sets the embed's thumbnail fi
 pointer to the embed
 */
 func (e *EmbedBuilder) SetRawThumbnail(thumb *disgord.EmbedThumbnail) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRawThumbnail(thumb) })
+	}
 	e.Thumbnail = thumb
 	return e
 }
 
 /*
-SetProvider allows you to set the provider of an embed. It will then return the pointer to the embed.
+SetProvider allows you to set the provider of an embed. It will then return the pointer to the embed. A copy of the
+provider is stored, so changing the ProviderBuilder afterwards does not change the embed.
 See the providerBuilder.go docs for some extra information
 */
 func (e *EmbedBuilder) SetProvider(provider *ProviderBuilder) *EmbedBuilder {
-	res, errs := provider.Finalize()
-	if errs != nil { // This should never run
-		e.addAllRawErrors(errs)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetProvider(provider) })
 	}
-	return e.SetRawProvider(res)
+	res, errs := provider.peek()
+	e.addAllRawErrors(`provider`, errs)
+	return e.SetRawProvider(res.DeepCopy().(*disgord.EmbedProvider))
 }
 
 /*
@@ -319,58 +722,83 @@ It will then return the pointer to the embed.
 See the providerBuilder.go docs for some extra information
 */
 func (e *EmbedBuilder) SetRawProvider(provider *disgord.EmbedProvider) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRawProvider(provider) })
+	}
 	e.Provider = provider
 	return e
 }
 
 /*
 SetFooter sets the embed's footer property to the FooterBuilder passed to it, then returns the pointer to the embed.
-Note that the FooterBuilder structure is `Finalize`d once added and should not be changed after being added. FooterBuilder errors
-will be propagated into the embed struct
+Note that a copy of the FooterBuilder structure is stored, so changing it afterwards does not change the embed, and its
+errors are left in place, so it can be shared between embeds. FooterBuilder errors will be propagated into the embed struct
 */
 func (e *EmbedBuilder) SetFooter(footer *FooterBuilder) *EmbedBuilder {
-	res, errs := footer.Finalize()
-	e.addAllRawErrors(errs)
-	return e.SetRawFooter(res)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetFooter(footer) })
+	}
+	res, errs := footer.peek()
+	e.addAllRawErrors(`footer`, errs)
+	return e.SetRawFooter(res.DeepCopy().(*disgord.EmbedFooter))
 }
 
 /*
-SetRawFooter takes a disgord.EmbedThumbnail and sets the embed's thumbnail field to it, then returns the
-pointer to the embed
+SetRawFooter takes a disgord.EmbedFooter and sets the embed's footer field to it, then returns the pointer to the
+embed. The footer is not set if its text would push the embed over its total character limit
+(This function fails silently)
 */
 func (e *EmbedBuilder) SetRawFooter(footer *disgord.EmbedFooter) *EmbedBuilder {
-	e.Footer = footer
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRawFooter(footer) })
+	}
+	if e.fitsBudget(`footer.text`, footerCharCount(e.Footer), footerCharCount(footer)) {
+		e.Footer = footer
+	}
 	return e
 }
 
 /*
 SetVideo sets the embed's video property to the VideoBuilder passed to it, then returns the pointer to the embed.
-Note that the VideoBuilder structure is `Finalize`d once added and should not be changed after being added
+Note that a copy of the VideoBuilder structure is stored, so changing it afterwards does not change the embed, and its
+errors are left in place, so it can be shared between embeds. All errors are propagated to the main embed
 */
 func (e *EmbedBuilder) SetVideo(vid *VideoBuilder) *EmbedBuilder {
-	res, errs := vid.Finalize()
-	e.addAllRawErrors(errs)
-	return e.SetRawVideo(res)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetVideo(vid) })
+	}
+	res, errs := vid.peek()
+	e.addAllRawErrors(`video`, errs)
+	return e.SetRawVideo(res.DeepCopy().(*disgord.EmbedVideo))
 }
 
 /*
 SetRawVideo takes a disgord.EmbedVideo and sets the embed's thumbnail field to it, then returns the pointer to
-the embed
+the embed. Discord drops videos from embeds sent by bots, so setting one is recorded as a warning, whatever its url
 */
 func (e *EmbedBuilder) SetRawVideo(vid *disgord.EmbedVideo) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRawVideo(vid) })
+	}
 	e.Video = vid
+	if vid != nil {
+		e.addRawError(validation.NewWarning(`video`, validation.ErrIgnoredProperty, nil, vid.URL))
+	}
 	return e
 }
 
 /*
 SetImage sets the embed's image property to the ImageBuilder passed to it, then returns the pointer to the embed.
-Note that the ImageBuilder structure is `Finalize`d once added and should not be changed after being added. ImageBuilder errors
-will be propagated into the embed struct
+Note that a copy of the ImageBuilder structure is stored, so changing it afterwards does not change the embed, and its
+errors are left in place, so it can be shared between embeds. ImageBuilder errors will be propagated into the embed struct
 */
 func (e *EmbedBuilder) SetImage(img *ImageBuilder) *EmbedBuilder {
-	res, errs := img.Finalize()
-	e.addAllRawErrors(errs)
-	return e.SetRawImage(res)
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetImage(img) })
+	}
+	res, errs := img.peek()
+	e.addAllRawErrors(`image`, errs)
+	return e.SetRawImage(res.DeepCopy().(*disgord.EmbedImage))
 }
 
 /*
@@ -378,20 +806,271 @@ SetRawImage takes a disgord.EmbedImage and sets the embed's image field to it, t
 embed
 */
 func (e *EmbedBuilder) SetRawImage(img *disgord.EmbedImage) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetRawImage(img) })
+	}
 	e.Image = img
 	return e
 }
 
 /*
 SetType checks if the embed type passed to it is valid. If it is, it sets the embed's type to that, otherwise it does
-nothing. It then returns the pointer to the embed
+nothing. It then returns the pointer to the embed. Clients ignore the type of embeds sent by bots, so types other than
+validation.RichEmbedType are recorded as a warning
 (This function fails silently)
 */
 func (e *EmbedBuilder) SetType(embedType string) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SetType(embedType) })
+	}
 	if validation.CheckTypeValid(embedType) {
 		e.Type = embedType
+		if embedType != validation.RichEmbedType {
+			e.addRawError(validation.NewWarning(`type`, validation.ErrIgnoredType, nil, embedType))
+		}
 	} else {
-		e.addError(validation.InvalidEmbedTypeErrTemplateString, embedType)
+		e.addError(`type`, validation.ErrInvalidType, nil, embedType)
+	}
+	return e
+}
+
+/*
+ClearTitle removes the embed's title, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearTitle() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearTitle() })
+	}
+	e.Title = ``
+	return e
+}
+
+/*
+ClearDescription removes the embed's description, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearDescription() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearDescription() })
+	}
+	e.Description = ``
+	return e
+}
+
+/*
+ClearURL removes the embed's main URL, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearURL() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearURL() })
+	}
+	e.URL = ``
+	return e
+}
+
+/*
+ClearColor removes the embed's highlight colour, leaving discord's default, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearColor() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearColor() })
+	}
+	e.Color = 0
+	return e
+}
+
+/*
+ClearTimestamp removes the embed's timestamp, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearTimestamp() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearTimestamp() })
+	}
+	e.Timestamp = disgord.Time{}
+	return e
+}
+
+/*
+ClearType removes the embed's type, leaving discord's default of validation.RichEmbedType, then returns the pointer to
+the embed
+*/
+func (e *EmbedBuilder) ClearType() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearType() })
+	}
+	e.Type = ``
+	return e
+}
+
+/*
+ClearFields removes all of the embed's fields, leaving the field list nil, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearFields() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearFields() })
+	}
+	e.Fields = nil
+	return e
+}
+
+/*
+ClearAuthor removes the embed's author, leaving it nil rather than an empty author, then returns the pointer to the
+embed
+*/
+func (e *EmbedBuilder) ClearAuthor() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearAuthor() })
 	}
+	e.Author = nil
 	return e
 }
+
+/*
+ClearFooter removes the embed's footer, leaving it nil rather than an empty footer, then returns the pointer to the
+embed
+*/
+func (e *EmbedBuilder) ClearFooter() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearFooter() })
+	}
+	e.Footer = nil
+	return e
+}
+
+/*
+ClearImage removes the embed's image, leaving it nil rather than an empty image, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearImage() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearImage() })
+	}
+	e.Image = nil
+	return e
+}
+
+/*
+ClearThumbnail removes the embed's thumbnail, leaving it nil rather than an empty thumbnail, then returns the pointer to
+the embed
+*/
+func (e *EmbedBuilder) ClearThumbnail() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearThumbnail() })
+	}
+	e.Thumbnail = nil
+	return e
+}
+
+/*
+ClearVideo removes the embed's video, leaving it nil rather than an empty video, then returns the pointer to the embed
+*/
+func (e *EmbedBuilder) ClearVideo() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearVideo() })
+	}
+	e.Video = nil
+	return e
+}
+
+/*
+ClearProvider removes the embed's provider, leaving it nil rather than an empty provider, then returns the pointer to
+the embed
+*/
+func (e *EmbedBuilder) ClearProvider() *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ClearProvider() })
+	}
+	e.Provider = nil
+	return e
+}
+
+/*
+withoutRecorded returns the validation errors in found that do not describe the same problem as one in recorded, so
+that warnings raised by a setter are not reported a second time by validation.ValidateEmbedWithLimits
+*/
+func withoutRecorded(recorded *[]error, found *[]error) *[]error {
+	if recorded == nil || found == nil {
+		return found
+	}
+	var res []error
+	for _, err := range *found {
+		duplicate := false
+		for _, old := range *recorded {
+			var oldErr, newErr *validation.Error
+			if errors.As(old, &oldErr) && errors.As(err, &newErr) &&
+				oldErr.Path == newErr.Path && oldErr.Code == newErr.Code && oldErr.Value == newErr.Value {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			res = append(res, err)
+		}
+	}
+	return &res
+}
+
+/*
+fieldNameError returns the problem with name as the name of the field at path, or nil if it is a valid field name
+*/
+func fieldNameError(path string, name string, limit int) *validation.Error {
+	if name == `` {
+		return validation.NewError(path, validation.ErrEmpty, nil, name)
+	}
+	if length := validation.CharCount(name); length > limit {
+		return validation.NewError(path, validation.ErrTooLong, limit, length)
+	}
+	return nil
+}
+
+/*
+splitValue splits value into a chunk of at most limit characters and the rest of the value. The chunk ends at the
+last line break that fits if there is one, and that line break is dropped. Otherwise it is cut between characters with
+validation.Cut, which keeps combined characters such as emoji sequences whole
+*/
+func splitValue(value string, limit int) (chunk string, rest string) {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value, ``
+	}
+	for i := limit; i > 0; i-- {
+		if runes[i] == '\n' {
+			return string(runes[:i]), string(runes[i+1:])
+		}
+	}
+	return validation.Cut(value, limit)
+}
+
+// copyEmbed returns a deep copy of embed, keeping a nil field list nil
+func copyEmbed(embed *disgord.Embed) *disgord.Embed {
+	if embed == nil {
+		return nil
+	}
+	res := embed.DeepCopy().(*disgord.Embed)
+	if embed.Fields == nil {
+		res.Fields = nil
+	}
+	return res
+}
+
+// fieldCharCount returns the number of characters a field counts towards the embed's total character limit
+func fieldCharCount(field *disgord.EmbedField) int {
+	if field == nil {
+		return 0
+	}
+	return validation.CharCount(field.Name) + validation.CharCount(field.Value)
+}
+
+// authorCharCount returns the number of characters an author counts towards the embed's total character limit
+func authorCharCount(author *disgord.EmbedAuthor) int {
+	if author == nil {
+		return 0
+	}
+	return validation.CharCount(author.Name)
+}
+
+// footerCharCount returns the number of characters a footer counts towards the embed's total character limit
+func footerCharCount(footer *disgord.EmbedFooter) int {
+	if footer == nil {
+		return 0
+	}
+	return validation.CharCount(footer.Text)
+}
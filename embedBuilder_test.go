@@ -1,8 +1,11 @@
 package disgobed
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
 	"github.com/maxatome/go-testdeep/td"
 )
@@ -32,3 +35,549 @@ func TestNewEmbed(tt *testing.T) {
 
 	t.Log(`EmbedBuilder.Finalize() test complete`)
 }
+
+/*
+TestEmbed_SubBuilderErrors tests that sub-builder errors are propagated with their paths placed beneath the embed
+*/
+func TestEmbed_SubBuilderErrors(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test field and footer errors are re-rooted`)
+	_, gotErrors := NewEmbed().
+		AddField(NewField().SetName(`ok`).SetValue(`ok`)).
+		AddField(NewField().SetName(``).SetValue(`ok`)).
+		SetFooter(NewFooter().SetIconURL(`nope`)).
+		Finalize()
+
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields[1].name`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`footer.icon_url`, validation.ErrInvalidURL, nil, `nope`),
+	})
+
+	t.Log(`EmbedBuilder sub-builder error test complete`)
+}
+
+/*
+TestEmbed_MultiByteLimits tests that limits are measured in characters rather than bytes
+*/
+func TestEmbed_MultiByteLimits(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test a 256 character japanese title is accepted`)
+	title := strings.Repeat(`日`, validation.LowerCharLimit)
+	gotEmbed, gotErrors := NewEmbed().SetTitle(title).Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed.Title, title)
+
+	t.Log(`2. test a 257 emoji title is rejected`)
+	title = strings.Repeat("\U0001F600", validation.LowerCharLimit+1)
+	gotEmbed, gotErrors = NewEmbed().SetTitle(title).Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`title`, validation.ErrTooLong, validation.LowerCharLimit, validation.LowerCharLimit+1),
+	})
+	t.Cmp(gotEmbed.Title, ``)
+}
+
+/*
+TestEmbed_Budget tests that the total character limit is tracked and enforced
+*/
+func TestEmbed_Budget(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test Used and Remaining track counted properties`)
+	e := NewEmbed().
+		SetTitle(`12345`).
+		SetURL(`https://not.counted`).
+		SetFooter(NewFooter().SetText(`12345`)).
+		SetAuthor(NewAuthor().SetName(`12345`))
+	t.Cmp(e.Used(), 15)
+	t.Cmp(e.Remaining(), validation.MaxTotalCharLimit-15)
+
+	t.Log(`2. test replacing a property only counts the new value`)
+	e.SetTitle(`123`)
+	t.Cmp(e.Used(), 13)
+
+	t.Log(`3. test fields that would exceed the total are rejected`)
+	e = NewEmbed()
+	value := strings.Repeat(`a`, validation.MiddleCharLimit)
+	for i := 0; i < 6; i++ {
+		e.AddField(NewField().SetName(`n`).SetValue(value))
+	}
+	t.Cmp(e.Used(), 5*(validation.MiddleCharLimit+1))
+	t.Cmp(len(e.Fields), 5)
+
+	t.Log(`4. test setters that would exceed the total are rejected`)
+	e.SetDescription(strings.Repeat(`a`, 1000))
+	t.Cmp(e.Description, ``)
+
+	_, gotErrors := e.Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields[5]`, validation.ErrTotalTooLong, validation.MaxTotalCharLimit, 6*(validation.MiddleCharLimit+1)),
+		validation.NewError(`description`, validation.ErrTotalTooLong, validation.MaxTotalCharLimit, 5*(validation.MiddleCharLimit+1)+1000),
+	})
+}
+
+/*
+TestEmbed_Truncation tests that truncation cuts values instead of dropping them and records warnings
+*/
+func TestEmbed_Truncation(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test embed setters truncate`)
+	desc := strings.Repeat(`a`, validation.UpperCharLimit+52)
+	embed := NewEmbed().EnableTruncation(validation.DefaultEllipsis).SetDescription(desc)
+	t.Cmp(embed.Errors, &[]error{
+		validation.NewWarning(`description`, validation.ErrTruncated, validation.UpperCharLimit, validation.UpperCharLimit+52),
+	})
+	gotEmbed, gotErrors := embed.Finalize()
+	t.Cmp(validation.CharCount(gotEmbed.Description), validation.UpperCharLimit)
+	t.Cmp(gotErrors, td.Nil())
+
+	t.Log(`2. test sub-builder setters truncate`)
+	field := NewField().EnableTruncation(`...`).SetValue(strings.Repeat(`b`, 2000))
+	t.Cmp(field.Errors, &[]error{
+		validation.NewWarning(`value`, validation.ErrTruncated, validation.MiddleCharLimit, 2000),
+	})
+	gotField, gotErrors := field.Finalize()
+	t.Cmp(gotField.Value, strings.Repeat(`b`, validation.MiddleCharLimit-3)+`...`)
+	t.Cmp(gotErrors, td.Nil())
+
+	t.Log(`3. test disabled truncation drops values`)
+	gotEmbed, _ = NewEmbed().EnableTruncation(``).DisableTruncation().SetDescription(desc).Finalize()
+	t.Cmp(gotEmbed.Description, ``)
+}
+
+/*
+TestEmbed_SetLimits tests that limit profiles are used by the embed and inherited by its sub-builders
+*/
+func TestEmbed_SetLimits(tt *testing.T) {
+	t := td.NewT(tt)
+
+	desc := strings.Repeat(`a`, 3000)
+
+	t.Log(`1. test the default profile rejects a 3000 character description`)
+	gotEmbed, _ := NewEmbed().SetDescription(desc).Finalize()
+	t.Cmp(gotEmbed.Description, ``)
+
+	t.Log(`2. test the current profile accepts it`)
+	gotEmbed, gotErrors := NewEmbed().SetLimits(validation.CurrentLimits).SetDescription(desc).Finalize()
+	t.Cmp(gotEmbed.Description, desc)
+	t.Cmp(gotErrors, td.Nil())
+
+	t.Log(`3. test sub-builders inherit the profile`)
+	limits := validation.CurrentLimits
+	limits.FieldName = 3
+	e := NewEmbed().SetLimits(limits)
+	_, gotErrors = e.NewField().SetName(`long`).Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`name`, validation.ErrTooLong, 3, 4),
+	})
+	t.Cmp(e.Remaining(), limits.Total)
+}
+
+/*
+TestEmbed_URLs tests that every url setter validates its input
+*/
+func TestEmbed_URLs(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test valid urls are set`)
+	gotEmbed, gotErrors := NewEmbed().
+		SetURL(`https://example.com`).
+		SetAuthor(NewAuthor().SetURL(`https://example.com/me`).SetIconURL(`attachment://me.png`)).
+		Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed.URL, `https://example.com`)
+	t.Cmp(gotEmbed.Author.URL, `https://example.com/me`)
+
+	t.Log(`2. test invalid urls are rejected`)
+	gotEmbed, gotErrors = NewEmbed().
+		SetURL(`example.com`).
+		SetAuthor(NewAuthor().SetURL(`attachment://me.png`)).
+		SetVideo(NewVideo().SetURL(`https://`)).
+		SetProvider(NewProvider().SetURL(`https://a b`)).
+		Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`url`, validation.ErrInvalidURL, nil, `example.com`),
+		validation.NewError(`author.url`, validation.ErrInvalidURL, nil, `attachment://me.png`),
+		validation.NewError(`video.url`, validation.ErrInvalidURL, nil, `https://`),
+		validation.NewError(`provider.url`, validation.ErrInvalidURL, nil, `https://a b`),
+	})
+	t.Cmp(gotEmbed.URL, ``)
+}
+
+/*
+TestEmbed_FailOn tests that lint warnings are recorded and that Validate respects the fail threshold
+*/
+func TestEmbed_FailOn(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test bad practices are recorded as warnings, which Finalize drops by default`)
+	embed := NewEmbed().
+		SetURL(`http://example.com`).
+		SetType(validation.LinkEmbedType).
+		SetImage(NewImage().SetProxyURL(`https://proxy.example.com/a.png`))
+	warnings := []error{
+		validation.NewWarning(`url`, validation.ErrInsecureURL, nil, `http://example.com`),
+		validation.NewWarning(`type`, validation.ErrIgnoredType, nil, validation.LinkEmbedType),
+		validation.NewWarning(`image.proxy_url`, validation.ErrIgnoredProperty, nil, `https://proxy.example.com/a.png`),
+	}
+	t.Cmp(embed.Errors, &warnings)
+	gotEmbed, gotErrors := embed.Clone().Finalize()
+	t.Cmp(gotEmbed.URL, `http://example.com`)
+	t.Cmp(gotEmbed.Type, validation.LinkEmbedType)
+	t.Cmp(gotErrors, td.Nil())
+	_, gotErrors = embed.FailOn(validation.SeverityWarning).Finalize()
+	t.Cmp(gotErrors, &warnings)
+
+	t.Log(`2. test warnings do not fail validation by default`)
+	t.Cmp(NewEmbed().SetURL(`http://example.com`).SetTitle(`title`).Validate(nil), td.Nil())
+
+	t.Log(`3. test warnings fail validation when asked to`)
+	t.Cmp(NewEmbed().FailOn(validation.SeverityWarning).SetURL(`http://example.com`).Validate(nil), &[]error{
+		validation.NewWarning(`url`, validation.ErrInsecureURL, nil, `http://example.com`),
+	})
+
+	t.Log(`4. test videos are reported as ignored whatever their url`)
+	t.Cmp(NewEmbed().SetVideo(NewVideo().SetURL(`https://example.com/a.mp4`)).Errors, &[]error{
+		validation.NewWarning(`video`, validation.ErrIgnoredProperty, nil, `https://example.com/a.mp4`),
+	})
+	t.Cmp(NewEmbed().SetVideo(NewVideo().SetURL(`ftp://example.com/a.mp4`)).FailOn(validation.SeverityWarning).Validate(nil), &[]error{
+		validation.NewError(`video.url`, validation.ErrInvalidURL, nil, `ftp://example.com/a.mp4`),
+		validation.NewWarning(`video`, validation.ErrIgnoredProperty, nil, ``), // The bad url was never set
+	})
+	t.Cmp(NewEmbed().SetRawVideo(&disgord.EmbedVideo{}).Errors, &[]error{
+		validation.NewWarning(`video`, validation.ErrIgnoredProperty, nil, ``),
+	})
+	t.Cmp(validation.BuiltinRules().Validate(&disgord.Embed{Video: &disgord.EmbedVideo{}}, nil, validation.DefaultLimits), &[]error{
+		validation.NewWarning(`video`, validation.ErrIgnoredProperty, nil, ``),
+	})
+}
+
+/*
+TestEmbed_Rules tests that custom rule sets and per call rules are used by Validate
+*/
+func TestEmbed_Rules(tt *testing.T) {
+	t := td.NewT(tt)
+
+	errNoFooter := errors.New(`footer missing`)
+	requireFooter := validation.NewRule(`require-footer`, func(target validation.Target) []error {
+		if target.Embed.Footer == nil {
+			return []error{validation.NewError(`footer`, errNoFooter, nil, nil)}
+		}
+		return nil
+	})
+
+	t.Log(`1. test per call rules`)
+	t.Cmp(NewEmbed().SetTitle(`title`).Validate(nil, requireFooter), &[]error{
+		validation.NewError(`footer`, errNoFooter, nil, nil),
+	})
+	t.Cmp(NewEmbed().SetTitle(`title`).Validate(nil), td.Nil())
+
+	t.Log(`2. test per embed rule sets`)
+	rules := validation.DefaultRules.Clone().Disable(validation.RuleTitleLength).Add(requireFooter)
+	embed := NewEmbed().SetRules(rules)
+	embed.Title = strings.Repeat(`a`, validation.LowerCharLimit+1)
+	t.Cmp(embed.Validate(nil), &[]error{
+		validation.NewError(`footer`, errNoFooter, nil, nil),
+	})
+}
+
+/*
+TestEmbed_AddSplitField tests that long values are spread across fields within the embed's limits
+*/
+func TestEmbed_AddSplitField(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test short values use one field`)
+	gotEmbed, gotErrors := NewEmbed().AddSplitField(`Name`, `value`, `Name (cont.)`).Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed.Fields, []*disgord.EmbedField{{Name: `Name`, Value: `value`}})
+
+	t.Log(`2. test long values are split between lines`)
+	line := strings.Repeat(`a`, 299)
+	value := strings.Join([]string{line, line, line, line, line}, "\n")
+	gotEmbed, gotErrors = NewEmbed().AddSplitField(`Log`, value, ZeroWidthFieldName).Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed.Fields, []*disgord.EmbedField{
+		{Name: `Log`, Value: strings.Join([]string{line, line, line}, "\n")},
+		{Name: ZeroWidthFieldName, Value: strings.Join([]string{line, line}, "\n")},
+	})
+
+	t.Log(`3. test values without line breaks are split between characters`)
+	gotEmbed, _ = NewEmbed().AddSplitField(`Data`, strings.Repeat(`b`, 1500), `Data (cont.)`).Finalize()
+	t.Cmp(gotEmbed.Fields, []*disgord.EmbedField{
+		{Name: `Data`, Value: strings.Repeat(`b`, validation.MiddleCharLimit)},
+		{Name: `Data (cont.)`, Value: strings.Repeat(`b`, 1500-validation.MiddleCharLimit)},
+	})
+
+	t.Log(`4. test the total character limit is respected and overflow is reported`)
+	gotEmbed, gotErrors = NewEmbed().
+		SetDescription(strings.Repeat(`c`, 2000)).
+		AddSplitField(`Big`, strings.Repeat(`d`, 5000), `Big`).
+		Finalize()
+	t.Cmp(validation.TotalCharacterCount(gotEmbed), validation.MaxTotalCharLimit)
+	t.Cmp(gotEmbed.Fields, td.Len(4))
+	t.Cmp(gotErrors, &[]error{validation.NewError(`fields[4]`, validation.ErrOverflow, nil, 5000-(4000-4*3))})
+
+	t.Log(`5. test the field count limit is respected`)
+	embed := NewEmbed()
+	for i := 0; i < validation.MaxFieldCount-1; i++ {
+		embed.AddRawField(&disgord.EmbedField{Name: `n`, Value: `v`})
+	}
+	gotEmbed, gotErrors = embed.AddSplitField(`Last`, strings.Repeat(`e`, 1100), `Last`).Finalize()
+	t.Cmp(gotEmbed.Fields, td.Len(validation.MaxFieldCount))
+	t.Cmp(gotErrors, &[]error{validation.NewError(`fields[25]`, validation.ErrOverflow, nil, 1100-validation.MiddleCharLimit)})
+
+	t.Log(`6. test bad names and empty values are rejected, reporting the text left out`)
+	gotEmbed, gotErrors = NewEmbed().AddSplitField(``, `value`, `cont`).AddSplitField(`name`, ``, `cont`).Finalize()
+	t.Cmp(gotEmbed.Fields, td.Empty())
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields[0].name`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`fields[0]`, validation.ErrOverflow, nil, 5),
+		validation.NewError(`fields[0].value`, validation.ErrEmpty, nil, ``),
+	})
+
+	t.Log(`7. test a bad continuation name reports the text left out`)
+	gotEmbed, gotErrors = NewEmbed().AddSplitField(`Log`, strings.Repeat(`f`, 1100), ``).Finalize()
+	t.Cmp(gotEmbed.Fields, td.Len(1))
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields[1].name`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`fields[1]`, validation.ErrOverflow, nil, 1100-validation.MiddleCharLimit),
+	})
+
+	t.Log(`8. test combined characters are not split between fields`)
+	family := "\U0001F469\u200d\U0001F469\u200d\U0001F467"
+	value = strings.Repeat(`g`, validation.MiddleCharLimit-2) + family
+	gotEmbed, gotErrors = NewEmbed().AddSplitField(`Log`, value, `Log`).Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed.Fields, []*disgord.EmbedField{
+		{Name: `Log`, Value: strings.Repeat(`g`, validation.MiddleCharLimit-2)},
+		{Name: `Log`, Value: family},
+	})
+}
+
+/*
+TestEmbed_Clone tests that cloned embeds and added sub-builders do not share state
+*/
+func TestEmbed_Clone(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test clones are deep copies`)
+	original := NewEmbed().
+		SetTitle(`original`).
+		SetAuthor(NewAuthor().SetName(`bot`)).
+		AddField(NewField().SetName(`n`).SetValue(`v`)).
+		SetURL(`ftp://example.com`)
+	clone := original.Clone()
+	clone.SetTitle(`clone`).SetAuthor(NewAuthor().SetName(`someone`))
+	clone.Fields[0].Value = `changed`
+	clone.SetColor(-1)
+	t.Cmp(original.Embed, &disgord.Embed{
+		Title:  `original`,
+		Author: &disgord.EmbedAuthor{Name: `bot`},
+		Fields: []*disgord.EmbedField{{Name: `n`, Value: `v`}},
+	})
+	t.Cmp(original.Errors, &[]error{validation.NewError(`url`, validation.ErrInvalidURL, nil, `ftp://example.com`)})
+	t.Cmp(clone.Errors, td.Ptr(td.Len(2)))
+
+	t.Log(`2. test sub-builders are copied when added`)
+	footer := NewFooter().SetText(`shared`)
+	first := NewEmbed().SetFooter(footer)
+	second := NewEmbed().SetFooter(footer)
+	footer.SetText(`changed`)
+	t.Cmp(first.Footer.Text, `shared`)
+	t.Cmp(second.Footer.Text, `shared`)
+	t.Cmp(first.Footer, td.Not(td.Shallow(second.Footer)))
+}
+
+/*
+TestEmbed_Immutable tests that immutable embeds are never changed by their setters
+*/
+func TestEmbed_Immutable(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test setters return changed copies`)
+	base := NewEmbed().SetColor(0x00ff00).SetFooter(NewFooter().SetText(`bot`)).Immutable()
+	changed := base.SetTitle(`pong`).AddSplitField(`log`, `a`, `log`).SetURL(`ftp://example.com`)
+	t.Cmp(base.Embed, &disgord.Embed{Color: 0x00ff00, Footer: &disgord.EmbedFooter{Text: `bot`}})
+	t.Cmp(base.Errors, td.Nil())
+	t.Cmp(changed.Embed, &disgord.Embed{
+		Title:  `pong`,
+		Color:  0x00ff00,
+		Footer: &disgord.EmbedFooter{Text: `bot`},
+		Fields: []*disgord.EmbedField{{Name: `log`, Value: `a`}},
+	})
+
+	t.Log(`2. test finalize leaves the error cache alone`)
+	gotEmbed, gotErrors := changed.Finalize()
+	t.Cmp(gotErrors, &[]error{validation.NewError(`url`, validation.ErrInvalidURL, nil, `ftp://example.com`)})
+	t.Cmp(changed.Errors, gotErrors)
+	gotEmbed.Title = `edited`
+	t.Cmp(changed.Title, `pong`)
+
+	t.Log(`3. test a shared base can be built on concurrently`)
+	done := make(chan *disgord.Embed)
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			embed, _ := base.SetTitle(strings.Repeat(`x`, i+1)).Finalize()
+			done <- embed
+		}(i)
+	}
+	for i := 0; i < 10; i++ {
+		t.Cmp(validation.CharCount((<-done).Title), td.Between(1, 10))
+	}
+	t.Cmp(base.Title, ``)
+
+	t.Log(`4. test a shared footer gives its errors to every embed built from it concurrently`)
+	shared := NewFooter().SetText(`bot`).SetIconURL(`ftp://example.com/icon.png`)
+	errs := make(chan *[]error)
+	for i := 0; i < 8; i++ {
+		go func(i int) {
+			_, embedErrs := base.SetTitle(strings.Repeat(`x`, i+1)).SetFooter(shared).Finalize()
+			errs <- embedErrs
+		}(i)
+	}
+	for i := 0; i < 8; i++ {
+		t.Cmp(<-errs, &[]error{
+			validation.NewError(`footer.icon_url`, validation.ErrInvalidURL, nil, `ftp://example.com/icon.png`),
+		})
+	}
+	t.Cmp(shared.Errors, td.Ptr(td.Len(1)))
+}
+
+/*
+TestEmbed_Clear tests that properties and components can be removed once set
+*/
+func TestEmbed_Clear(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test every property of the embed can be cleared`)
+	embed := NewEmbed().
+		SetTitle(`title`).
+		SetDescription(`description`).
+		SetURL(`https://example.com`).
+		SetColor(0xff0000).
+		SetCurrentTimestamp().
+		SetType(validation.RichEmbedType).
+		AddField(NewField().SetName(`n`).SetValue(`v`)).
+		SetAuthor(NewAuthor().SetName(`author`)).
+		SetFooter(NewFooter().SetText(`footer`)).
+		SetImage(NewImage().SetURL(`https://example.com/i.png`)).
+		SetThumbnail(NewThumbnail().SetURL(`https://example.com/t.png`)).
+		SetProvider(NewProvider().SetName(`provider`)).
+		SetRawVideo(&disgord.EmbedVideo{URL: `https://example.com/v.mp4`})
+	gotEmbed, _ := embed.
+		ClearTitle().
+		ClearDescription().
+		ClearURL().
+		ClearColor().
+		ClearTimestamp().
+		ClearType().
+		ClearFields().
+		ClearAuthor().
+		ClearFooter().
+		ClearImage().
+		ClearThumbnail().
+		ClearVideo().
+		ClearProvider().
+		Finalize()
+	t.Cmp(gotEmbed, &disgord.Embed{})
+	t.Cmp(embed.Used(), 0)
+	data, err := embed.ToJSON()
+	t.CmpNoError(err)
+	t.Cmp(string(data), `{}`)
+
+	t.Log(`2. test sub-builder properties can be cleared`)
+	t.Cmp(NewAuthor().SetName(`a`).SetURL(`https://example.com`).ClearURL().EmbedAuthor,
+		&disgord.EmbedAuthor{Name: `a`})
+	t.Cmp(NewFooter().SetText(`f`).SetIconURL(`https://example.com/i.png`).ClearIconURL().ClearText().EmbedFooter,
+		&disgord.EmbedFooter{})
+	t.Cmp(NewImage().SetURL(`https://example.com/i.png`).SetHW(1, 2).ClearHW().EmbedImage,
+		&disgord.EmbedImage{URL: `https://example.com/i.png`})
+	t.Cmp(NewThumbnail().SetURL(`https://example.com/t.png`).ClearURL().EmbedThumbnail, &disgord.EmbedThumbnail{})
+	t.Cmp(NewVideo().SetURL(`https://example.com/v.mp4`).SetHW(1, 2).ClearHW().ClearURL().EmbedVideo,
+		&disgord.EmbedVideo{})
+	t.Cmp(NewProvider().SetName(`p`).SetURL(`https://example.com`).ClearName().EmbedProvider,
+		&disgord.EmbedProvider{URL: `https://example.com`})
+
+	t.Log(`3. test immutable embeds are left unchanged`)
+	base := NewEmbed().SetTitle(`title`).SetFooter(NewFooter().SetText(`footer`)).Immutable()
+	cleared := base.ClearFooter()
+	t.Cmp(base.Footer, &disgord.EmbedFooter{Text: `footer`})
+	t.Cmp(cleared.Footer, td.Nil())
+}
+
+/*
+TestSetHW tests that only the invalid dimension is reported when setting both at once
+*/
+func TestSetHW(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test only the failing dimension is reported`)
+	image := NewImage().SetHW(100, 0)
+	t.Cmp(image.EmbedImage, &disgord.EmbedImage{})
+	t.Cmp(image.Errors, &[]error{validation.NewError(`width`, validation.ErrNotPositive, 0, 0)})
+	t.Cmp(NewThumbnail().SetHW(-1, 100).Errors, &[]error{validation.NewError(`height`, validation.ErrNotPositive, 0, -1)})
+	t.Cmp(NewVideo().SetHW(0, 0).Errors, &[]error{
+		validation.NewError(`height`, validation.ErrNotPositive, 0, 0),
+		validation.NewError(`width`, validation.ErrNotPositive, 0, 0),
+	})
+
+	t.Log(`2. test valid dimensions are both set`)
+	t.Cmp(NewVideo().SetHW(1, 2).EmbedVideo, &disgord.EmbedVideo{Height: 1, Width: 2})
+}
+
+/*
+TestSetColor tests that the colour limit is inclusive
+*/
+func TestSetColor(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test the largest colour value is accepted`)
+	embed := NewEmbed().SetColor(validation.MaxColorValue)
+	t.Cmp(embed.Color, validation.MaxColorValue)
+	t.Cmp(embed.Errors, td.Ptr(td.Empty()))
+
+	t.Log(`2. test values past the limit are rejected`)
+	embed = NewEmbed().SetColor(validation.MaxColorValue + 1)
+	t.Cmp(embed.Color, 0)
+	t.Cmp(embed.Errors, &[]error{validation.NewError(`color`, validation.ErrOutOfRange, validation.MaxColorValue, validation.MaxColorValue+1)})
+}
+
+/*
+TestStrict tests that strict mode panics at the setter that breaks a rule
+*/
+func TestStrict(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test the breaking setter panics with the typed error`)
+	long := strings.Repeat(`a`, validation.LowerCharLimit+1)
+	embed := NewEmbed().Strict()
+	t.CmpPanic(func() { embed.SetTitle(long) },
+		validation.NewError(`title`, validation.ErrTooLong, validation.LowerCharLimit, validation.LowerCharLimit+1))
+	t.CmpPanic(func() { embed.SetColor(-1) }, td.Isa((*validation.Error)(nil)))
+	t.CmpPanic(func() { NewAuthor().Strict().SetURL(`ftp://example.com`) },
+		validation.NewError(`url`, validation.ErrInvalidURL, nil, `ftp://example.com`))
+	t.Cmp(embed.Title, ``)
+
+	t.Log(`2. test sub-builders made by the embed inherit strict mode`)
+	t.CmpPanic(func() { embed.NewField().SetName(``) }, validation.NewError(`name`, validation.ErrEmpty, nil, ``))
+	t.CmpPanic(func() { embed.NewAuthor().SetName(long) },
+		validation.NewError(`name`, validation.ErrTooLong, validation.LowerCharLimit, validation.LowerCharLimit+1))
+	t.CmpPanic(func() { embed.NewFooter().SetIconURL(`ftp://example.com`) }, td.Isa((*validation.Error)(nil)))
+	t.CmpPanic(func() { embed.NewImage().SetHeight(0) }, validation.NewError(`height`, validation.ErrNotPositive, 0, 0))
+	t.CmpPanic(func() { embed.NewThumbnail().SetWidth(-1) }, td.Isa((*validation.Error)(nil)))
+	t.CmpPanic(func() { embed.NewVideo().SetURL(`not a url`) }, td.Isa((*validation.Error)(nil)))
+	t.CmpPanic(func() { embed.NewProvider().SetURL(`not a url`) }, td.Isa((*validation.Error)(nil)))
+	t.CmpNotPanic(func() { NewEmbed().NewField().SetName(``) })
+
+	t.Log(`3. test warnings do not panic`)
+	t.CmpNotPanic(func() {
+		embed.SetURL(`http://example.com`).
+			SetType(validation.LinkEmbedType).
+			SetImage(embed.NewImage().SetURL(`http://example.com/a.png`).SetProxyURL(`https://example.com/b.png`))
+	})
+	t.Cmp(embed.Errors, td.Ptr(td.Len(4)))
+	t.Cmp(validation.HasErrors(embed.Errors), false)
+}
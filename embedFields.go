@@ -0,0 +1,184 @@
+package disgobed
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+)
+
+/*
+FieldCount returns the number of fields in the embed
+*/
+func (e *EmbedBuilder) FieldCount() int {
+	return len(e.Fields)
+}
+
+/*
+FindField returns the index of the first field called name along with the field itself. If no field has that name, it
+returns -1 and nil
+*/
+func (e *EmbedBuilder) FindField(name string) (int, *disgord.EmbedField) {
+	for i, f := range e.Fields {
+		if f != nil && f.Name == name {
+			return i, f
+		}
+	}
+	return -1, nil
+}
+
+/*
+InsertFieldAt takes a FieldBuilder structure and inserts it into the embed at index, moving the fields from index
+onwards along by one, then returns the pointer to the embed. Index may be anything from 0 to FieldCount(), where
+FieldCount() adds the field to the end. Note that a copy of the FieldBuilder structure is stored, so changing it
+afterwards does not change the embed. All errors are propagated to the main embed
+(This function fails silently)
+*/
+func (e *EmbedBuilder) InsertFieldAt(index int, field *FieldBuilder) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.InsertFieldAt(index, field) })
+	}
+	res, errs := field.peek()
+	e.addAllRawErrors(fmt.Sprintf(`fields[%d]`, index), errs)
+	return e.InsertRawFieldAt(index, res.DeepCopy().(*disgord.EmbedField))
+}
+
+/*
+InsertRawFieldAt takes a disgord.EmbedField structure and inserts it into the embed at index, moving the fields from
+index onwards along by one, then returns the pointer to the embed. The field is not inserted if index is not between 0
+and FieldCount(), if the embed already has 25 fields, or if the field would push the embed over its total character
+limit
+(This function fails silently)
+*/
+func (e *EmbedBuilder) InsertRawFieldAt(index int, field *disgord.EmbedField) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.InsertRawFieldAt(index, field) })
+	}
+	path := fmt.Sprintf(`fields[%d]`, index)
+	if index < 0 || index > len(e.Fields) {
+		e.addError(`fields`, validation.ErrOutOfRange, len(e.Fields), index)
+	} else if limit := e.opts.limits().FieldCount; len(e.Fields) >= limit {
+		e.addError(`fields`, validation.ErrTooMany, limit, len(e.Fields)+1)
+	} else if e.fitsBudget(path, 0, fieldCharCount(field)) {
+		e.Fields = append(e.Fields, nil)
+		copy(e.Fields[index+1:], e.Fields[index:])
+		e.Fields[index] = field
+	}
+	return e
+}
+
+/*
+RemoveField removes the field at index from the embed, moving the fields after it back by one, then returns the pointer
+to the embed. Nothing is removed if index is not between 0 and FieldCount()-1
+(This function fails silently)
+*/
+func (e *EmbedBuilder) RemoveField(index int) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.RemoveField(index) })
+	}
+	if !e.hasFieldAt(index) {
+		return e
+	}
+	e.Fields = append(e.Fields[:index], e.Fields[index+1:]...)
+	return e
+}
+
+/*
+RemoveFieldByName removes the first field called name from the embed (see FindField), then returns the pointer to the
+embed. Nothing is removed if no field has that name
+(This function fails silently)
+*/
+func (e *EmbedBuilder) RemoveFieldByName(name string) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.RemoveFieldByName(name) })
+	}
+	if index, _ := e.FindField(name); index >= 0 {
+		return e.RemoveField(index)
+	}
+	e.addError(`fields`, validation.ErrNotFound, nil, name)
+	return e
+}
+
+/*
+ReplaceField takes a FieldBuilder structure and puts it in place of the field at index, then returns the pointer to the
+embed. Note that a copy of the FieldBuilder structure is stored, so changing it afterwards does not change the embed.
+All errors are propagated to the main embed
+(This function fails silently)
+*/
+func (e *EmbedBuilder) ReplaceField(index int, field *FieldBuilder) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ReplaceField(index, field) })
+	}
+	res, errs := field.peek()
+	e.addAllRawErrors(fmt.Sprintf(`fields[%d]`, index), errs)
+	return e.ReplaceRawField(index, res.DeepCopy().(*disgord.EmbedField))
+}
+
+/*
+ReplaceRawField takes a disgord.EmbedField structure and puts it in place of the field at index, then returns the
+pointer to the embed. The field is not replaced if index is not between 0 and FieldCount()-1, or if the new field would
+push the embed over its total character limit
+(This function fails silently)
+*/
+func (e *EmbedBuilder) ReplaceRawField(index int, field *disgord.EmbedField) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.ReplaceRawField(index, field) })
+	}
+	if !e.hasFieldAt(index) {
+		return e
+	}
+	if e.fitsBudget(fmt.Sprintf(`fields[%d]`, index), fieldCharCount(e.Fields[index]), fieldCharCount(field)) {
+		e.Fields[index] = field
+	}
+	return e
+}
+
+/*
+MoveField moves the field at from so that it ends up at index to, shifting the fields in between to make room, then
+returns the pointer to the embed. Nothing is moved if either index is not between 0 and FieldCount()-1
+(This function fails silently)
+*/
+func (e *EmbedBuilder) MoveField(from int, to int) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.MoveField(from, to) })
+	}
+	if !e.hasFieldAt(from) || !e.hasFieldAt(to) {
+		return e
+	}
+	field := e.Fields[from]
+	if from < to {
+		copy(e.Fields[from:to], e.Fields[from+1:to+1])
+	} else {
+		copy(e.Fields[to+1:from+1], e.Fields[to:from])
+	}
+	e.Fields[to] = field
+	return e
+}
+
+/*
+SortFields reorders the embed's fields using less, which reports whether field a should come before field b, then
+returns the pointer to the embed. Fields that less considers equal keep their current order
+
+	embed.SortFields(func(a, b *disgord.EmbedField) bool { return a.Name < b.Name })
+*/
+func (e *EmbedBuilder) SortFields(less func(a *disgord.EmbedField, b *disgord.EmbedField) bool) *EmbedBuilder {
+	if e.opts.immutable {
+		return e.onCopy(func(c *EmbedBuilder) { c.SortFields(less) })
+	}
+	sort.SliceStable(e.Fields, func(i, j int) bool {
+		return less(e.Fields[i], e.Fields[j])
+	})
+	return e
+}
+
+/*
+hasFieldAt checks whether the embed has a field at index, recording an error if it does not
+*/
+func (e *EmbedBuilder) hasFieldAt(index int) bool {
+	if index < 0 || index >= len(e.Fields) {
+		e.addError(`fields`, validation.ErrOutOfRange, len(e.Fields)-1, index)
+		return false
+	}
+	return true
+}
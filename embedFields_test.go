@@ -0,0 +1,114 @@
+package disgobed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+// fieldNames returns the names of the fields in embed, in order
+func fieldNames(embed *EmbedBuilder) []string {
+	var res []string
+	for _, f := range embed.Fields {
+		res = append(res, f.Name)
+	}
+	return res
+}
+
+/*
+TestEmbed_FieldManipulation tests fields can be inserted, removed, replaced, moved, sorted and found
+*/
+func TestEmbed_FieldManipulation(tt *testing.T) {
+	t := td.NewT(tt)
+
+	newEmbed := func() *EmbedBuilder {
+		return NewEmbed().AddRawFields(
+			&disgord.EmbedField{Name: `b`, Value: `2`},
+			&disgord.EmbedField{Name: `d`, Value: `4`},
+			&disgord.EmbedField{Name: `a`, Value: `1`},
+		)
+	}
+
+	t.Log(`1. test fields can be found and counted`)
+	embed := newEmbed()
+	t.Cmp(embed.FieldCount(), 3)
+	index, field := embed.FindField(`d`)
+	t.Cmp(index, 1)
+	t.Cmp(field, &disgord.EmbedField{Name: `d`, Value: `4`})
+	index, field = embed.FindField(`z`)
+	t.Cmp(index, -1)
+	t.Cmp(field, td.Nil())
+
+	t.Log(`2. test fields can be inserted`)
+	embed.InsertFieldAt(0, NewField().SetName(`first`).SetValue(`0`)).
+		InsertRawFieldAt(4, &disgord.EmbedField{Name: `last`, Value: `5`}).
+		InsertFieldAt(2, NewField().SetName(`c`).SetValue(``))
+	t.Cmp(fieldNames(embed), []string{`first`, `b`, `c`, `d`, `a`, `last`})
+	t.Cmp(embed.Errors, &[]error{validation.NewError(`fields[2].value`, validation.ErrEmpty, nil, ``)})
+
+	t.Log(`3. test fields can be removed, replaced and moved`)
+	embed = newEmbed().
+		RemoveField(0).
+		RemoveFieldByName(`a`).
+		ReplaceField(0, NewField().SetName(`e`).SetValue(`5`)).
+		AddRawField(&disgord.EmbedField{Name: `f`, Value: `6`}).
+		AddRawField(&disgord.EmbedField{Name: `g`, Value: `7`}).
+		MoveField(2, 0)
+	t.Cmp(fieldNames(embed), []string{`g`, `e`, `f`})
+	t.Cmp(embed.MoveField(0, 2).Fields[2], &disgord.EmbedField{Name: `g`, Value: `7`})
+	t.Cmp(embed.Errors, td.Nil())
+
+	t.Log(`4. test fields can be sorted`)
+	embed = newEmbed().SortFields(func(a, b *disgord.EmbedField) bool { return a.Name < b.Name })
+	t.Cmp(fieldNames(embed), []string{`a`, `b`, `d`})
+
+	t.Log(`5. test bad indexes and names are reported`)
+	_, gotErrors := newEmbed().
+		InsertRawFieldAt(5, &disgord.EmbedField{Name: `x`, Value: `x`}).
+		RemoveField(-1).
+		RemoveFieldByName(`z`).
+		ReplaceRawField(3, &disgord.EmbedField{Name: `x`, Value: `x`}).
+		MoveField(0, 3).
+		Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields`, validation.ErrOutOfRange, 3, 5),
+		validation.NewError(`fields`, validation.ErrOutOfRange, 2, -1),
+		validation.NewError(`fields`, validation.ErrNotFound, nil, `z`),
+		validation.NewError(`fields`, validation.ErrOutOfRange, 2, 3),
+		validation.NewError(`fields`, validation.ErrOutOfRange, 2, 3),
+	})
+
+	t.Log(`6. test the field count and total character limits are kept`)
+	embed = NewEmbed()
+	for i := 0; i < validation.MaxFieldCount; i++ {
+		embed.AddRawField(&disgord.EmbedField{Name: `n`, Value: `v`})
+	}
+	_, gotErrors = embed.InsertRawFieldAt(0, &disgord.EmbedField{Name: `x`, Value: `x`}).Finalize()
+	t.Cmp(embed.FieldCount(), validation.MaxFieldCount)
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields`, validation.ErrTooMany, validation.MaxFieldCount, validation.MaxFieldCount+1),
+	})
+
+	embed = newEmbed().SetDescription(strings.Repeat(`a`, 2048)).SetTitle(strings.Repeat(`t`, 256))
+	big := &disgord.EmbedField{Name: `big`, Value: strings.Repeat(`v`, 1024)}
+	embed.AddRawField(big).AddRawField(big).AddRawField(big)
+	used := embed.Used()
+	_, gotErrors = embed.
+		ReplaceRawField(0, &disgord.EmbedField{Name: `b`, Value: strings.Repeat(`v`, 1024)}).
+		InsertRawFieldAt(0, &disgord.EmbedField{Name: `x`, Value: strings.Repeat(`v`, 1024)}).
+		Finalize()
+	t.Cmp(embed.Used(), used)
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`fields[0]`, validation.ErrTotalTooLong, validation.MaxTotalCharLimit, used+1023),
+		validation.NewError(`fields[0]`, validation.ErrTotalTooLong, validation.MaxTotalCharLimit, used+1025),
+	})
+
+	t.Log(`7. test immutable embeds are left unchanged`)
+	base := newEmbed().Immutable()
+	changed := base.RemoveFieldByName(`b`).SortFields(func(a, b *disgord.EmbedField) bool { return a.Name > b.Name })
+	t.Cmp(fieldNames(base), []string{`b`, `d`, `a`})
+	t.Cmp(fieldNames(changed), []string{`d`, `a`})
+}
@@ -1,8 +1,6 @@
 package disgobed
 
 import (
-	"fmt"
-
 	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
 )
@@ -13,17 +11,47 @@ FieldBuilder wraps the disgord.EmbedField type and adds features
 type FieldBuilder struct {
 	*disgord.EmbedField
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before a field is
 added. Finalize will also purge the error cache!
+Warnings are dropped, so a nil result means the field has no problems discord will reject
 */
 func (f *FieldBuilder) Finalize() (*disgord.EmbedField, *[]error) {
 	defer func(f *FieldBuilder) { f.Errors = nil }(f)
+	return f.EmbedField, validation.FilterSeverity(f.Errors, f.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a FieldBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (f *FieldBuilder) peek() (*disgord.EmbedField, *[]error) {
 	return f.EmbedField, f.Errors
 }
 
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the FieldBuilder
+*/
+func (f *FieldBuilder) Strict() *FieldBuilder {
+	f.opts.strict = true
+	return f
+}
+
+/*
+SetLimits makes the FieldBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the FieldBuilder
+*/
+func (f *FieldBuilder) SetLimits(limits validation.Limits) *FieldBuilder {
+	f.opts.profile = &limits
+	return f
+}
+
 /*
 NewField creates and returns a new empty field object
 */
@@ -35,14 +63,60 @@ func NewField() *FieldBuilder {
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in FieldBuilder. If the pointer is nil a new error slice
-is created. This function takes the same inputs as fmt.Sprintf
+addError records a validation error for the property at path in the error slice stored in FieldBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
+*/
+func (f *FieldBuilder) addError(path string, code error, limit interface{}, value interface{}) {
+	if f.Errors == nil {
+		f.Errors = &[]error{}
+	}
+	err := validation.NewError(path, code, limit, value)
+	f.opts.check(err)
+	*f.Errors = append(*f.Errors, err)
+}
+
+/*
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
 */
-func (f *FieldBuilder) addError(format string, values ...interface{}) {
+func (f *FieldBuilder) addRawError(err error) {
+	f.opts.check(err)
 	if f.Errors == nil {
 		f.Errors = &[]error{}
 	}
-	*f.Errors = append(*f.Errors, fmt.Errorf(format, values...))
+	*f.Errors = append(*f.Errors, err)
+}
+
+/*
+fit applies the FieldBuilder's truncation policy to the value of the property at path, recording a warning if the value was
+cut to fit limit
+*/
+func (f *FieldBuilder) fit(path string, value string, limit int) string {
+	value, warning := f.opts.fit(path, value, limit)
+	if warning != nil {
+		f.addRawError(warning)
+	}
+	return value
+}
+
+/*
+EnableTruncation makes the FieldBuilder cut values that are too long down to their limit, appending ellipsis to mark the cut,
+instead of dropping them. Each cut is recorded as a warning. Pass validation.DefaultEllipsis for the standard marker.
+It then returns the pointer to the FieldBuilder
+*/
+func (f *FieldBuilder) EnableTruncation(ellipsis string) *FieldBuilder {
+	f.opts.truncate = true
+	f.opts.ellipsis = ellipsis
+	return f
+}
+
+/*
+DisableTruncation restores the default behaviour of dropping values that are too long, then returns the pointer to the
+FieldBuilder
+*/
+func (f *FieldBuilder) DisableTruncation() *FieldBuilder {
+	f.opts.truncate = false
+	return f
 }
 
 /*
@@ -55,38 +129,43 @@ func (f *FieldBuilder) SetInline(isInline bool) *FieldBuilder {
 
 /*
 SetName sets the name of the field then returns the pointer to the FieldBuilder. The discord API limits FieldBuilder names to 256
-characters, so this function will do nothing if len(name) > 256. FieldBuilder names must also not be empty, so this function
-will do nothing if name == ``
+characters, so this function will do nothing if validation.CharCount(name) > 256. FieldBuilder names must also not be
+empty, so this function will do nothing if name == ``. If truncation is enabled, names that are too long are cut down
+instead
 (This function fails silently)
 */
 func (f *FieldBuilder) SetName(name string) *FieldBuilder {
-	if len(name) <= validation.LowerCharLimit {
+	limit := f.opts.limits().FieldName
+	name = f.fit(`name`, name, limit)
+	if length := validation.CharCount(name); length <= limit {
 		if name == `` {
-			f.addError(validation.ValueIsEmptyErrString, `field name`)
+			f.addError(`name`, validation.ErrEmpty, nil, name)
 		} else {
 			f.Name = name
 		}
 	} else {
-		f.addError(validation.CharacterCountExceedsLimitErrTemplateString, `field name`, validation.LowerCharLimit, len(name), name)
+		f.addError(`name`, validation.ErrTooLong, limit, length)
 	}
 	return f
 }
 
 /*
 SetValue sets the value of the field then returns the pointer to the FieldBuilder. The discord API limits FieldBuilder values to 1024
-characters, so this function will do nothing if len(name) > 1024. FieldBuilder values must not be empty, so this function will
-do nothing if val == ``
+characters, so this function will do nothing if validation.CharCount(val) > 1024. FieldBuilder values must not be empty,
+so this function will do nothing if val == ``. If truncation is enabled, values that are too long are cut down instead
 (This function fails silently)
 */
 func (f *FieldBuilder) SetValue(val string) *FieldBuilder {
-	if len(val) <= validation.MiddleCharLimit {
+	limit := f.opts.limits().FieldValue
+	val = f.fit(`value`, val, limit)
+	if length := validation.CharCount(val); length <= limit {
 		if val == `` {
-			f.addError(validation.ValueIsEmptyErrString, `field value`)
+			f.addError(`value`, validation.ErrEmpty, nil, val)
 		} else {
 			f.Value = val
 		}
 	} else {
-		f.addError(validation.CharacterCountExceedsLimitLongErrTemplateString, `field value`, validation.MiddleCharLimit, len(val))
+		f.addError(`value`, validation.ErrTooLong, limit, length)
 	}
 	return f
 }
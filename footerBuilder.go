@@ -1,8 +1,6 @@
 package disgobed
 
 import (
-	"fmt"
-
 	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
 )
@@ -13,17 +11,47 @@ FooterBuilder wraps the disgord.EmbedFooter type and adds features
 type FooterBuilder struct {
 	*disgord.EmbedFooter
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before a footer is
 attached. Finalize will also purge the error cache!
+Warnings are dropped, so a nil result means the footer has no problems discord will reject
 */
 func (f *FooterBuilder) Finalize() (*disgord.EmbedFooter, *[]error) {
 	defer func(f *FooterBuilder) { f.Errors = nil }(f)
+	return f.EmbedFooter, validation.FilterSeverity(f.Errors, f.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a FooterBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (f *FooterBuilder) peek() (*disgord.EmbedFooter, *[]error) {
 	return f.EmbedFooter, f.Errors
 }
 
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the FooterBuilder
+*/
+func (f *FooterBuilder) Strict() *FooterBuilder {
+	f.opts.strict = true
+	return f
+}
+
+/*
+SetLimits makes the FooterBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the FooterBuilder
+*/
+func (f *FooterBuilder) SetLimits(limits validation.Limits) *FooterBuilder {
+	f.opts.profile = &limits
+	return f
+}
+
 /*
 NewFooter creates and returns a new empty footer
 */
@@ -35,55 +63,152 @@ func NewFooter() *FooterBuilder {
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in FooterBuilder. If the pointer is nil a new error slice
-is created. This function takes the same inputs as fmt.Sprintf
+addError records a validation error for the property at path in the error slice stored in FooterBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
+*/
+func (f *FooterBuilder) addError(path string, code error, limit interface{}, value interface{}) {
+	if f.Errors == nil {
+		f.Errors = &[]error{}
+	}
+	err := validation.NewError(path, code, limit, value)
+	f.opts.check(err)
+	*f.Errors = append(*f.Errors, err)
+}
+
+/*
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
 */
-func (f *FooterBuilder) addError(format string, values ...interface{}) {
+func (f *FooterBuilder) addRawError(err error) {
+	f.opts.check(err)
 	if f.Errors == nil {
 		f.Errors = &[]error{}
 	}
-	*f.Errors = append(*f.Errors, fmt.Errorf(format, values...))
+	*f.Errors = append(*f.Errors, err)
+}
+
+/*
+fit applies the FooterBuilder's truncation policy to the value of the property at path, recording a warning if the value was
+cut to fit limit
+*/
+func (f *FooterBuilder) fit(path string, value string, limit int) string {
+	value, warning := f.opts.fit(path, value, limit)
+	if warning != nil {
+		f.addRawError(warning)
+	}
+	return value
+}
+
+/*
+EnableTruncation makes the FooterBuilder cut values that are too long down to their limit, appending ellipsis to mark the cut,
+instead of dropping them. Each cut is recorded as a warning. Pass validation.DefaultEllipsis for the standard marker.
+It then returns the pointer to the FooterBuilder
+*/
+func (f *FooterBuilder) EnableTruncation(ellipsis string) *FooterBuilder {
+	f.opts.truncate = true
+	f.opts.ellipsis = ellipsis
+	return f
+}
+
+/*
+DisableTruncation restores the default behaviour of dropping values that are too long, then returns the pointer to the
+FooterBuilder
+*/
+func (f *FooterBuilder) DisableTruncation() *FooterBuilder {
+	f.opts.truncate = false
+	return f
 }
 
 /*
 SetIconURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the FooterBuilder (if
-the string does not start with one of these, no URL will be added). It then returns the pointer to the FooterBuilder structure
+the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the FooterBuilder structure
+http:// urls are accepted but recorded as a warning
 (This function fails silently)
 */
 func (f *FooterBuilder) SetIconURL(iconUrl string) *FooterBuilder {
-	if validation.CheckValidIconURL(iconUrl) {
+	if err := validation.ValidateURL(`icon_url`, iconUrl, true, f.opts.limits()); err == nil {
 		f.IconURL = iconUrl
+		if warning := validation.LintURL(`icon_url`, iconUrl); warning != nil {
+			f.addRawError(warning)
+		}
 	} else {
-		f.addError(validation.InvalidUrlErrTemplateString, `footer iconUrl`, iconUrl)
+		f.addRawError(err)
 	}
 	return f
 }
 
+/*
+SetIconAttachment sets the footer icon url to the `attachment://` url of the given attachment, then returns the pointer to the
+FooterBuilder. The attachment must be uploaded with the message, such as by passing its registry to
+MessageBuilder.SetAttachments. A nil attachment is not added
+(This function fails silently)
+*/
+func (f *FooterBuilder) SetIconAttachment(attachment *Attachment) *FooterBuilder {
+	if attachment == nil {
+		f.addError(`icon_url`, validation.ErrMissingAttachment, nil, validation.AttachmentURLPrefix)
+		return f
+	}
+	return f.SetIconURL(attachment.URL())
+}
+
 /*
 SetText takes a string and sets the FooterBuilder's text to that value. It then returns the pointer to the FooterBuilder. The discord
-API limits FooterBuilder values to 2048 characters, so this function will do nothing if len(val) > 2048
+API limits FooterBuilder values to 2048 characters, so this function will do nothing if validation.CharCount(val) > 2048.
+If truncation is enabled, text that is too long is cut down instead
 (This function fails silently)
 */
 func (f *FooterBuilder) SetText(val string) *FooterBuilder {
-	if len(val) <= validation.UpperCharLimit {
+	limit := f.opts.limits().FooterText
+	val = f.fit(`text`, val, limit)
+	if length := validation.CharCount(val); length <= limit {
 		f.Text = val
 	} else {
-		f.addError(validation.CharacterCountExceedsLimitLongErrTemplateString, `footer text`, validation.UpperCharLimit, len(val))
+		f.addError(`text`, validation.ErrTooLong, limit, length)
 	}
 	return f
 }
 
 /*
 SetProxyIconURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the FooterBuilder
-(if the string does not start with one of these, no URL will be added). It then returns the pointer to the FooterBuilder
+(if the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the FooterBuilder
 structure
+Discord ignores proxy urls on input, so setting one is recorded as a warning. http:// urls are also recorded as
+warnings
 (This function fails silently)
 */
 func (f *FooterBuilder) SetProxyIconURL(proxyIconUrl string) *FooterBuilder {
-	if validation.CheckValidIconURL(proxyIconUrl) {
+	if err := validation.ValidateURL(`proxy_icon_url`, proxyIconUrl, true, f.opts.limits()); err == nil {
 		f.ProxyIconURL = proxyIconUrl
+		if warning := validation.LintURL(`proxy_icon_url`, proxyIconUrl); warning != nil {
+			f.addRawError(warning)
+		}
+		f.addRawError(validation.NewWarning(`proxy_icon_url`, validation.ErrIgnoredProperty, nil, proxyIconUrl))
 	} else {
-		f.addError(validation.InvalidUrlErrTemplateString, `footer proxyIconUrl`, proxyIconUrl)
+		f.addRawError(err)
 	}
 	return f
 }
+
+/*
+ClearText removes the footer's text, then returns the pointer to the FooterBuilder
+*/
+func (f *FooterBuilder) ClearText() *FooterBuilder {
+	f.Text = ``
+	return f
+}
+
+/*
+ClearIconURL removes the footer's icon url, then returns the pointer to the FooterBuilder
+*/
+func (f *FooterBuilder) ClearIconURL() *FooterBuilder {
+	f.IconURL = ``
+	return f
+}
+
+/*
+ClearProxyIconURL removes the footer's proxy icon url, then returns the pointer to the FooterBuilder
+*/
+func (f *FooterBuilder) ClearProxyIconURL() *FooterBuilder {
+	f.ProxyIconURL = ``
+	return f
+}
@@ -0,0 +1,155 @@
+package disgobed
+
+import (
+	"github.com/andersfylling/disgord"
+)
+
+/*
+FromEmbed creates an EmbedBuilder holding a deep copy of embed, such as one returned by disgord when fetching a message,
+so it can be changed and sent again without changing the original. The existing content is checked by the same setters
+that would have built it, and any problems are recorded in the builder's errors, so stale or invalid values are reported
+before the embed is sent. The content is copied as it is, so values with problems are kept until they are replaced.
+The properties discord fills in itself when it returns an embed, which are the proxy urls, video, provider and type,
+are copied but not checked, so a fetched embed is not reported as setting properties discord ignores.
+A nil embed gives an empty EmbedBuilder
+*/
+func FromEmbed(embed *disgord.Embed) *EmbedBuilder {
+	res := NewEmbed()
+	if embed == nil {
+		return res
+	}
+	res.Embed = copyEmbed(embed)
+	res.Errors = specFromEmbed(embed).withoutServerFields().apply(NewEmbed()).Errors
+	return res
+}
+
+/*
+FromField creates a FieldBuilder holding a deep copy of field, recording any problems with its existing content in the
+builder's errors (see FromEmbed). A nil field gives an empty FieldBuilder
+*/
+func FromField(field *disgord.EmbedField) *FieldBuilder {
+	res := NewField()
+	if field == nil {
+		return res
+	}
+	res.EmbedField = field.DeepCopy().(*disgord.EmbedField)
+	res.Errors = specFromField(field).apply(NewField()).Errors
+	return res
+}
+
+/*
+FromAuthor creates an AuthorBuilder holding a deep copy of author, recording any problems with its existing content in
+the builder's errors (see FromEmbed). The proxy icon url is filled in by discord, so it is copied but not checked. A nil
+author gives an empty AuthorBuilder
+*/
+func FromAuthor(author *disgord.EmbedAuthor) *AuthorBuilder {
+	res := NewAuthor()
+	if author == nil {
+		return res
+	}
+	res.EmbedAuthor = author.DeepCopy().(*disgord.EmbedAuthor)
+	spec := specFromAuthor(author)
+	spec.ProxyIconURL = ``
+	res.Errors = spec.apply(NewAuthor()).Errors
+	return res
+}
+
+/*
+FromFooter creates a FooterBuilder holding a deep copy of footer, recording any problems with its existing content in
+the builder's errors (see FromEmbed). The proxy icon url is filled in by discord, so it is copied but not checked. A nil
+footer gives an empty FooterBuilder
+*/
+func FromFooter(footer *disgord.EmbedFooter) *FooterBuilder {
+	res := NewFooter()
+	if footer == nil {
+		return res
+	}
+	res.EmbedFooter = footer.DeepCopy().(*disgord.EmbedFooter)
+	spec := specFromFooter(footer)
+	spec.ProxyIconURL = ``
+	res.Errors = spec.apply(NewFooter()).Errors
+	return res
+}
+
+/*
+FromImage creates an ImageBuilder holding a deep copy of image, recording any problems with its existing content in the
+builder's errors (see FromEmbed). The proxy url is filled in by discord, so it is copied but not checked. A nil image
+gives an empty ImageBuilder
+*/
+func FromImage(image *disgord.EmbedImage) *ImageBuilder {
+	res := NewImage()
+	if image == nil {
+		return res
+	}
+	res.EmbedImage = image.DeepCopy().(*disgord.EmbedImage)
+	spec := specFromImage(image)
+	spec.ProxyURL = ``
+	res.Errors = spec.applyImage(NewImage()).Errors
+	return res
+}
+
+/*
+FromThumbnail creates a ThumbnailBuilder holding a deep copy of thumb, recording any problems with its existing content
+in the builder's errors (see FromEmbed). The proxy url is filled in by discord, so it is copied but not checked. A nil
+thumbnail gives an empty ThumbnailBuilder
+*/
+func FromThumbnail(thumb *disgord.EmbedThumbnail) *ThumbnailBuilder {
+	res := NewThumbnail()
+	if thumb == nil {
+		return res
+	}
+	res.EmbedThumbnail = thumb.DeepCopy().(*disgord.EmbedThumbnail)
+	spec := specFromThumbnail(thumb)
+	spec.ProxyURL = ``
+	res.Errors = spec.applyThumbnail(NewThumbnail()).Errors
+	return res
+}
+
+/*
+FromVideo creates a VideoBuilder holding a deep copy of vid, recording any problems with its existing content in the
+builder's errors (see FromEmbed). A nil video gives an empty VideoBuilder
+*/
+func FromVideo(vid *disgord.EmbedVideo) *VideoBuilder {
+	res := NewVideo()
+	if vid == nil {
+		return res
+	}
+	res.EmbedVideo = vid.DeepCopy().(*disgord.EmbedVideo)
+	res.Errors = specFromVideo(vid).apply(NewVideo()).Errors
+	return res
+}
+
+/*
+FromProvider creates a ProviderBuilder holding a deep copy of provider, recording any problems with its existing
+content in the builder's errors (see FromEmbed). A nil provider gives an empty ProviderBuilder
+*/
+func FromProvider(provider *disgord.EmbedProvider) *ProviderBuilder {
+	res := NewProvider()
+	if provider == nil {
+		return res
+	}
+	res.EmbedProvider = provider.DeepCopy().(*disgord.EmbedProvider)
+	res.Errors = specFromProvider(provider).apply(NewProvider()).Errors
+	return res
+}
+
+/*
+withoutServerFields clears the properties discord fills in itself when it returns an embed, so that checking a fetched
+embed does not report them as properties discord ignores. It then returns the pointer to the embedSpec
+*/
+func (s *embedSpec) withoutServerFields() *embedSpec {
+	s.Type, s.Video, s.Provider = ``, nil, nil
+	if s.Image != nil {
+		s.Image.ProxyURL = ``
+	}
+	if s.Thumbnail != nil {
+		s.Thumbnail.ProxyURL = ``
+	}
+	if s.Author != nil {
+		s.Author.ProxyIconURL = ``
+	}
+	if s.Footer != nil {
+		s.Footer.ProxyIconURL = ``
+	}
+	return s
+}
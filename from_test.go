@@ -0,0 +1,143 @@
+package disgobed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestFromEmbed tests existing embeds can be wrapped back into builders
+*/
+func TestFromEmbed(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test the embed is deep copied`)
+	original := &disgord.Embed{
+		Title:  `Status`,
+		Color:  0x00ff00,
+		Author: &disgord.EmbedAuthor{Name: `bot`},
+		Fields: []*disgord.EmbedField{{Name: `state`, Value: `up`}},
+	}
+	embed := FromEmbed(original)
+	t.Cmp(embed.Errors, td.Nil())
+	embed.SetTitle(`Changed`)
+	embed.Fields[0].Value = `down`
+	embed.Author.Name = `someone else`
+	t.Cmp(original, &disgord.Embed{
+		Title:  `Status`,
+		Color:  0x00ff00,
+		Author: &disgord.EmbedAuthor{Name: `bot`},
+		Fields: []*disgord.EmbedField{{Name: `state`, Value: `up`}},
+	})
+	gotEmbed, gotErrors := embed.Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed.Title, `Changed`)
+
+	t.Log(`2. test invalid content is kept and reported`)
+	long := strings.Repeat(`a`, validation.LowerCharLimit+1)
+	embed = FromEmbed(&disgord.Embed{
+		Title:  long,
+		Image:  &disgord.EmbedImage{URL: `https://example.com/a.png`, ProxyURL: `https://media.example.com/a.png`},
+		Fields: []*disgord.EmbedField{{Name: `ok`, Value: `ok`}, {Name: ``, Value: `x`}},
+	})
+	t.Cmp(embed.Title, long)
+	t.Cmp(embed.Errors, &[]error{
+		validation.NewError(`title`, validation.ErrTooLong, validation.LowerCharLimit, validation.LowerCharLimit+1),
+		validation.NewError(`fields[1].name`, validation.ErrEmpty, nil, ``),
+	})
+	t.Cmp(embed.Image.ProxyURL, `https://media.example.com/a.png`)
+
+	t.Log(`3. test nil embeds give empty builders`)
+	embed = FromEmbed(nil)
+	t.Cmp(embed.Embed, &disgord.Embed{})
+	t.Cmp(embed.Errors, td.Nil())
+
+	t.Log(`4. test properties discord fills in are not reported`)
+	fetched := &disgord.Embed{
+		Title: `Deploy finished`,
+		Type:  validation.LinkEmbedType,
+		URL:   `https://example.com/deploys/42`,
+		Color: 0x00ff00,
+		Image: &disgord.EmbedImage{
+			URL:      `https://example.com/graph.png`,
+			ProxyURL: `https://media.discordapp.net/external/abc/https/example.com/graph.png`,
+			Height:   400,
+			Width:    800,
+		},
+		Thumbnail: &disgord.EmbedThumbnail{
+			URL:      `https://example.com/logo.png`,
+			ProxyURL: `https://media.discordapp.net/external/def/https/example.com/logo.png`,
+			Height:   64,
+			Width:    64,
+		},
+		Video:    &disgord.EmbedVideo{URL: `https://example.com/replay.mp4`, Height: 720, Width: 1280},
+		Provider: &disgord.EmbedProvider{Name: `Example CI`, URL: `https://example.com`},
+		Author: &disgord.EmbedAuthor{
+			Name:         `ci`,
+			IconURL:      `https://example.com/ci.png`,
+			ProxyIconURL: `https://images-ext-1.discordapp.net/external/ghi/https/example.com/ci.png`,
+		},
+		Footer: &disgord.EmbedFooter{
+			Text:         `build 42`,
+			IconURL:      `https://example.com/build.png`,
+			ProxyIconURL: `https://images-ext-1.discordapp.net/external/jkl/https/example.com/build.png`,
+		},
+		Fields: []*disgord.EmbedField{{Name: `duration`, Value: `3m`, Inline: true}},
+	}
+	embed = FromEmbed(fetched)
+	t.Cmp(embed.Errors, td.Nil())
+	gotEmbed, gotErrors = embed.FailOn(validation.SeverityWarning).Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed, fetched)
+	t.Cmp(FromImage(fetched.Image).Errors, td.Nil())
+	t.Cmp(FromThumbnail(fetched.Thumbnail).Errors, td.Nil())
+	t.Cmp(FromAuthor(fetched.Author).Errors, td.Nil())
+	t.Cmp(FromFooter(fetched.Footer).Errors, td.Nil())
+}
+
+/*
+TestFromParts tests existing embed parts can be wrapped back into builders
+*/
+func TestFromParts(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test parts are deep copied`)
+	field := &disgord.EmbedField{Name: `a`, Value: `b`, Inline: true}
+	fieldBuilder := FromField(field).SetValue(`c`)
+	t.Cmp(field.Value, `b`)
+	gotField, gotErrors := fieldBuilder.Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotField, &disgord.EmbedField{Name: `a`, Value: `c`, Inline: true})
+
+	thumb := &disgord.EmbedThumbnail{URL: `https://example.com/t.png`, Height: 1, Width: 2}
+	thumbBuilder := FromThumbnail(thumb).SetHW(3, 4)
+	t.Cmp(thumb.Height, 1)
+	t.Cmp(thumbBuilder.EmbedThumbnail, &disgord.EmbedThumbnail{URL: `https://example.com/t.png`, Height: 3, Width: 4})
+
+	t.Log(`2. test invalid content is reported`)
+	t.Cmp(FromAuthor(&disgord.EmbedAuthor{Name: `bot`, URL: `ftp://example.com`}).Errors, &[]error{
+		validation.NewError(`url`, validation.ErrInvalidURL, nil, `ftp://example.com`),
+	})
+	t.Cmp(FromFooter(&disgord.EmbedFooter{Text: `text`, IconURL: `http://example.com/i.png`}).Errors, &[]error{
+		validation.NewWarning(`icon_url`, validation.ErrInsecureURL, nil, `http://example.com/i.png`),
+	})
+	t.Cmp(FromImage(&disgord.EmbedImage{URL: `https://example.com/i.png`, Width: -1}).Errors, &[]error{
+		validation.NewError(`width`, validation.ErrNotPositive, 0, -1),
+	})
+	t.Cmp(FromVideo(&disgord.EmbedVideo{URL: `https://example.com/v.mp4`}).Errors, td.Nil())
+	t.Cmp(FromProvider(&disgord.EmbedProvider{Name: `provider`}).EmbedProvider,
+		&disgord.EmbedProvider{Name: `provider`})
+
+	t.Log(`3. test nil parts give empty builders`)
+	t.Cmp(FromField(nil).EmbedField, &disgord.EmbedField{})
+	t.Cmp(FromAuthor(nil).EmbedAuthor, &disgord.EmbedAuthor{})
+	t.Cmp(FromFooter(nil).EmbedFooter, &disgord.EmbedFooter{})
+	t.Cmp(FromImage(nil).EmbedImage, &disgord.EmbedImage{})
+	t.Cmp(FromThumbnail(nil).EmbedThumbnail, &disgord.EmbedThumbnail{})
+	t.Cmp(FromVideo(nil).EmbedVideo, &disgord.EmbedVideo{})
+	t.Cmp(FromProvider(nil).EmbedProvider, &disgord.EmbedProvider{})
+}
@@ -3,6 +3,8 @@ module github.com/Nightmarlin/disgobed
 go 1.14
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/andersfylling/disgord v0.17.3
 	github.com/maxatome/go-testdeep v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
 )
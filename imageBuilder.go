@@ -1,7 +1,8 @@
 package disgobed
 
 import (
-	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
@@ -11,17 +12,47 @@ import (
 type ImageBuilder struct {
 	*disgord.EmbedImage
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before an image is
 sent. Finalize will also purge the error cache!
+Warnings are dropped, so a nil result means the image has no problems discord will reject
 */
 func (i *ImageBuilder) Finalize() (*disgord.EmbedImage, *[]error) {
 	defer func(i *ImageBuilder) { i.Errors = nil }(i)
+	return i.EmbedImage, validation.FilterSeverity(i.Errors, i.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a ImageBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (i *ImageBuilder) peek() (*disgord.EmbedImage, *[]error) {
 	return i.EmbedImage, i.Errors
 }
 
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the ImageBuilder
+*/
+func (i *ImageBuilder) Strict() *ImageBuilder {
+	i.opts.strict = true
+	return i
+}
+
+/*
+SetLimits makes the ImageBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the ImageBuilder
+*/
+func (i *ImageBuilder) SetLimits(limits validation.Limits) *ImageBuilder {
+	i.opts.profile = &limits
+	return i
+}
+
 /*
 NewImage creates and returns an empty image structure
 */
@@ -33,40 +64,119 @@ func NewImage() *ImageBuilder {
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in AuthorBuilder. If the pointer is nil a new error slice
-is created. this function takes the same inputs as fmt.Sprintf
+NewImageFromBytes creates an image that shows the image in data. The image is registered with attachments under the
+given file name and the image's url and dimensions are set from it. See ImageBuilder.SetImageData
 */
-func (i *ImageBuilder) addError(format string, values ...interface{}) {
+func NewImageFromBytes(attachments *Attachments, name string, data []byte) *ImageBuilder {
+	return NewImage().SetImageData(attachments, name, data)
+}
+
+/*
+NewImageFromReader works like NewImageFromBytes, but reads the image from r
+*/
+func NewImageFromReader(attachments *Attachments, name string, r io.Reader) *ImageBuilder {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		res := NewImage()
+		res.addRawError(err)
+		return res
+	}
+	return NewImageFromBytes(attachments, name, data)
+}
+
+/*
+SetImageData registers the image in data with attachments under the given file name, then sets the image's url to the
+attachment and its height and width to those of the image. It then returns the pointer to the ImageBuilder. The image
+must be a png, jpeg, gif or webp, otherwise a validation.ErrUnsupportedImage or validation.ErrCorruptImage error is
+recorded and nothing is registered. If name has no extension, that of the image format is added
+(This function fails silently)
+*/
+func (i *ImageBuilder) SetImageData(attachments *Attachments, name string, data []byte) *ImageBuilder {
+	if attachments == nil {
+		i.addError(`url`, validation.ErrMissingAttachment, nil, name)
+		return i
+	}
+	attachment, info, err := attachments.addImage(`url`, name, data)
+	if err != nil {
+		i.addRawError(err)
+		return i
+	}
+	return i.SetAttachment(attachment).SetHW(info.Height, info.Width)
+}
+
+/*
+addError records a validation error for the property at path in the error slice stored in ImageBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
+*/
+func (i *ImageBuilder) addError(path string, code error, limit interface{}, value interface{}) {
+	if i.Errors == nil {
+		i.Errors = &[]error{}
+	}
+	err := validation.NewError(path, code, limit, value)
+	i.opts.check(err)
+	*i.Errors = append(*i.Errors, err)
+}
+
+/*
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
+*/
+func (i *ImageBuilder) addRawError(err error) {
+	i.opts.check(err)
 	if i.Errors == nil {
 		i.Errors = &[]error{}
 	}
-	*i.Errors = append(*i.Errors, fmt.Errorf(format, values...))
+	*i.Errors = append(*i.Errors, err)
 }
 
 /*
 SetURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the ImageBuilder (if
-the string does not start with one of these, no URL will be added). It then returns the pointer to the ImageBuilder structure
+the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the ImageBuilder structure
+http:// urls are accepted but recorded as a warning
 (This function fails silently)
 */
 func (i *ImageBuilder) SetURL(url string) *ImageBuilder {
-	if validation.CheckValidIconURL(url) {
+	if err := validation.ValidateURL(`url`, url, true, i.opts.limits()); err == nil {
 		i.URL = url
+		if warning := validation.LintURL(`url`, url); warning != nil {
+			i.addRawError(warning)
+		}
 	} else {
-		i.addError(validation.InvalidUrlErrTemplateString, `image url`, url)
+		i.addRawError(err)
 	}
 	return i
 }
 
+/*
+SetAttachment sets the image url to the `attachment://` url of the given attachment, then returns the pointer to the
+ImageBuilder. The attachment must be uploaded with the message, such as by passing its registry to
+MessageBuilder.SetAttachments. A nil attachment is not added
+(This function fails silently)
+*/
+func (i *ImageBuilder) SetAttachment(attachment *Attachment) *ImageBuilder {
+	if attachment == nil {
+		i.addError(`url`, validation.ErrMissingAttachment, nil, validation.AttachmentURLPrefix)
+		return i
+	}
+	return i.SetURL(attachment.URL())
+}
+
 /*
 SetProxyURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the ImageBuilder (if
-the string does not start with one of these, no URL will be added). It then returns the pointer to the ImageBuilder structure
+the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the ImageBuilder structure
+Discord ignores proxy urls on input, so setting one is recorded as a warning. http:// urls are also recorded as
+warnings
 (This function fails silently)
 */
 func (i *ImageBuilder) SetProxyURL(proxyUrl string) *ImageBuilder {
-	if validation.CheckValidIconURL(proxyUrl) {
+	if err := validation.ValidateURL(`proxy_url`, proxyUrl, true, i.opts.limits()); err == nil {
 		i.ProxyURL = proxyUrl
+		if warning := validation.LintURL(`proxy_url`, proxyUrl); warning != nil {
+			i.addRawError(warning)
+		}
+		i.addRawError(validation.NewWarning(`proxy_url`, validation.ErrIgnoredProperty, nil, proxyUrl))
 	} else {
-		i.addError(validation.InvalidUrlErrTemplateString, `image proxyUrl`, proxyUrl)
+		i.addRawError(err)
 	}
 	return i
 }
@@ -81,7 +191,12 @@ func (i *ImageBuilder) SetHW(h int, w int) *ImageBuilder {
 		i.Height = h
 		i.Width = w
 	} else {
-		i.addError(validation.InvalidHWErrTemplateString, `image`, h, `image`, w)
+		if h <= 0 {
+			i.addError(`height`, validation.ErrNotPositive, 0, h)
+		}
+		if w <= 0 {
+			i.addError(`width`, validation.ErrNotPositive, 0, w)
+		}
 	}
 	return i
 }
@@ -95,7 +210,7 @@ func (i *ImageBuilder) SetHeight(h int) *ImageBuilder {
 	if h > 0 {
 		i.Height = h
 	} else {
-		i.addError(validation.ValueNotBetweenErrTemplateString, `image height`, h, 0, `infinity`)
+		i.addError(`height`, validation.ErrNotPositive, 0, h)
 	}
 	return i
 }
@@ -109,7 +224,32 @@ func (i *ImageBuilder) SetWidth(w int) *ImageBuilder {
 	if w > 0 {
 		i.Width = w
 	} else {
-		i.addError(validation.ValueNotBetweenErrTemplateString, `image width`, w, 0, `infinity`)
+		i.addError(`width`, validation.ErrNotPositive, 0, w)
 	}
 	return i
 }
+
+/*
+ClearURL removes the image's url, then returns the pointer to the ImageBuilder
+*/
+func (i *ImageBuilder) ClearURL() *ImageBuilder {
+	i.URL = ``
+	return i
+}
+
+/*
+ClearProxyURL removes the image's proxy url, then returns the pointer to the ImageBuilder
+*/
+func (i *ImageBuilder) ClearProxyURL() *ImageBuilder {
+	i.ProxyURL = ``
+	return i
+}
+
+/*
+ClearHW removes the image's height and width, then returns the pointer to the ImageBuilder
+*/
+func (i *ImageBuilder) ClearHW() *ImageBuilder {
+	i.Height = 0
+	i.Width = 0
+	return i
+}
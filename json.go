@@ -0,0 +1,59 @@
+package disgobed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+/*
+FromJSON reads an embed in the shape the discord API uses and returns an EmbedBuilder holding it. Every property is set
+through the builder's setters, so invalid values are left out and recorded in the builder's errors with the JSON path
+they were read from, such as `fields[2].value`. The returned error is only set if data is not valid JSON for an embed,
+which includes JSON with properties an embed does not have, such as a misspelt `colour`
+*/
+func FromJSON(data []byte) (*EmbedBuilder, error) {
+	var spec embedSpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New(`json: unexpected data after the embed`)
+	}
+	return spec.apply(NewEmbed()), nil
+}
+
+/*
+ReadJSON works like FromJSON, but reads the embed from r
+*/
+func ReadJSON(r io.Reader) (*EmbedBuilder, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
+/*
+ToJSON writes the embed in the exact shape the discord API expects. Unset properties, including an unset timestamp, are
+left out. This method does not purge the error cache
+*/
+func (e *EmbedBuilder) ToJSON() ([]byte, error) {
+	return json.Marshal(specFromEmbed(e.Embed))
+}
+
+/*
+WriteJSON works like ToJSON, but writes the embed to w
+*/
+func (e *EmbedBuilder) WriteJSON(w io.Writer) error {
+	data, err := e.ToJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,115 @@
+package disgobed
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestJSON tests embeds can be read from and written to the discord API format
+*/
+func TestJSON(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test embeds are read through the setters`)
+	embed, err := FromJSON([]byte(`{
+		"title": "Canned",
+		"description": "A canned embed",
+		"url": "https://example.com",
+		"timestamp": "2020-05-01T12:30:00Z",
+		"color": 255,
+		"author": {"name": "bot", "icon_url": "https://example.com/bot.png"},
+		"footer": {"text": "footer"},
+		"image": {"url": "https://example.com/image.png", "height": 10, "width": 20},
+		"fields": [{"name": "a", "value": "b", "inline": true}]
+	}`))
+	t.CmpNoError(err)
+	gotEmbed, gotErrors := embed.Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed, &disgord.Embed{
+		Title:       `Canned`,
+		Description: `A canned embed`,
+		URL:         `https://example.com`,
+		Timestamp:   disgord.Time{Time: time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)},
+		Color:       255,
+		Author:      &disgord.EmbedAuthor{Name: `bot`, IconURL: `https://example.com/bot.png`},
+		Footer:      &disgord.EmbedFooter{Text: `footer`},
+		Image:       &disgord.EmbedImage{URL: `https://example.com/image.png`, Height: 10, Width: 20},
+		Fields:      []*disgord.EmbedField{{Name: `a`, Value: `b`, Inline: true}},
+	})
+
+	t.Log(`2. test invalid values are reported with json paths`)
+	embed, err = ReadJSON(strings.NewReader(`{
+		"title": "` + strings.Repeat(`a`, validation.LowerCharLimit+1) + `",
+		"timestamp": "yesterday",
+		"color": -5,
+		"thumbnail": {"url": "ftp://example.com/a.png", "width": -1},
+		"fields": [{"name": "ok", "value": "ok"}, {"name": "", "value": "x"}]
+	}`))
+	t.CmpNoError(err)
+	_, gotErrors = embed.Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`title`, validation.ErrTooLong, validation.LowerCharLimit, validation.LowerCharLimit+1),
+		validation.NewError(`timestamp`, validation.ErrInvalidTimestamp, nil, `yesterday`),
+		validation.NewError(`color`, validation.ErrOutOfRange, validation.MaxColorValue, -5),
+		validation.NewError(`fields[1].name`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`thumbnail.url`, validation.ErrInvalidURL, nil, `ftp://example.com/a.png`),
+		validation.NewError(`thumbnail.width`, validation.ErrNotPositive, 0, -1),
+	})
+
+	embed, err = ReadJSON(strings.NewReader(`{"color": ""}`))
+	t.CmpNoError(err)
+	_, gotErrors = embed.Finalize()
+	t.Cmp(gotErrors, &[]error{validation.NewError(`color`, validation.ErrInvalidColor, nil, ``)})
+
+	big := `{"name": "` + strings.Repeat(`n`, validation.LowerCharLimit) + `", "value": "` +
+		strings.Repeat(`v`, validation.MiddleCharLimit) + `"}`
+	embed, err = FromJSON([]byte(`{
+		"description": "` + strings.Repeat(`d`, validation.UpperCharLimit) + `",
+		"fields": [null, ` + big + `, ` + big + `, ` + big + `, ` + big + `, {"name": "", "value": "x"}]
+	}`))
+	t.CmpNoError(err)
+	t.Cmp(embed.Fields, td.Len(4))
+	t.Cmp(embed.Errors, &[]error{
+		validation.NewError(`fields[0]`, validation.ErrEmpty, nil, nil),
+		validation.NewError(`fields[4]`, validation.ErrTotalTooLong, validation.MaxTotalCharLimit,
+			validation.UpperCharLimit+4*(validation.LowerCharLimit+validation.MiddleCharLimit)),
+		validation.NewError(`fields[5].name`, validation.ErrEmpty, nil, ``),
+	})
+
+	t.Log(`3. test malformed json is rejected`)
+	_, err = FromJSON([]byte(`{"title": 5}`))
+	t.CmpError(err)
+	_, err = FromJSON([]byte(`{"title": "t", "colour": 255}`))
+	t.CmpError(err)
+	t.Cmp(err.Error(), td.Contains(`colour`))
+	_, err = FromJSON([]byte(`{"title": "t", "fields": [{"name": "n", "value": "v", "inlined": true}]}`))
+	t.CmpError(err)
+	_, err = FromJSON([]byte(`{"title": "t"} {"title": "u"}`))
+	t.CmpError(err)
+
+	t.Log(`4. test embeds are written in the discord api format and round trip`)
+	source := NewEmbed().
+		SetTitle(`Round trip`).
+		SetCustomTimestamp(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)).
+		AddField(NewField().SetName(`n`).SetValue(`v`)).
+		SetThumbnail(NewThumbnail().SetURL(`https://example.com/t.png`).SetHW(1, 2))
+	data, err := source.ToJSON()
+	t.CmpNoError(err)
+	t.Cmp(string(data), `{"title":"Round trip","timestamp":"2021-01-02T03:04:05Z",`+
+		`"thumbnail":{"url":"https://example.com/t.png","height":1,"width":2},"fields":[{"name":"n","value":"v"}]}`)
+
+	var buf bytes.Buffer
+	t.CmpNoError(NewEmbed().SetTitle(`no timestamp`).WriteJSON(&buf))
+	t.Cmp(buf.String(), `{"title":"no timestamp"}`)
+
+	loaded, err := FromJSON(data)
+	t.CmpNoError(err)
+	t.Cmp(loaded.Embed, source.Embed)
+}
@@ -0,0 +1,137 @@
+/*
+Package md composes and escapes discord markdown, so that untrusted text such as usernames shows up exactly as it was
+written instead of changing the formatting around it or mentioning people. See Node for composing markdown, and Escape
+for escaping text by hand
+*/
+package md
+
+import (
+	"regexp"
+	"strings"
+)
+
+/*
+Raw is text that is already discord markdown. It is never escaped, so it must never hold untrusted text
+*/
+type Raw string
+
+/*
+Context describes where in a piece of markdown text is placed, as the characters that need escaping depend on it
+*/
+type Context int
+
+const (
+	// TextContext is ordinary markdown text, where formatting, masked links and mentions are all rendered
+	TextContext Context = iota
+
+	// CodeBlockContext is the inside of a fenced ``` code block, which only a ``` fence can end
+	CodeBlockContext
+
+	// InlineCodeContext is the inside of `inline code`, which any backtick ends
+	InlineCodeContext
+)
+
+// codeFence opens and closes a code block
+const codeFence = "```"
+
+// zeroWidthSpace is placed inside syntax to stop discord recognising it, without changing how the text looks
+const zeroWidthSpace = "\u200b"
+
+// markdownEscaper backslash-escapes the characters discord treats as formatting, including the brackets of masked links
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	"`", "\\`",
+	`|`, `\|`,
+	`>`, `\>`,
+	`[`, `\[`,
+	`]`, `\]`,
+)
+
+// mentionPattern matches @everyone, @here and the user and role mention syntax, capturing everything after the @
+var mentionPattern = regexp.MustCompile(`@(everyone|here|[!&]?[0-9]{17,20})`)
+
+/*
+Escape makes text safe to place in ordinary markdown text. Formatting characters (*, _, ~, `, |, >) and the brackets of
+masked links are escaped with backslashes, and @everyone, @here and user and role mentions are broken up with a zero
+width space so they are shown as written but never mention anyone
+
+	md.Escape(`*bold* @everyone`) == `\*bold\* @` + "\u200b" + `everyone`
+*/
+func Escape(text string) string {
+	return EscapeMentions(markdownEscaper.Replace(text))
+}
+
+/*
+EscapeMentions breaks up @everyone, @here and user and role mentions in text with a zero width space, leaving any
+other markdown as it is
+*/
+func EscapeMentions(text string) string {
+	return mentionPattern.ReplaceAllString(text, `@`+zeroWidthSpace+`$1`)
+}
+
+/*
+EscapeCodeBlock makes text safe to place inside a fenced code block. Runs of backticks are broken up with zero width
+spaces so the text can never close the block early. Nothing else is rendered inside a code block, so nothing else is
+changed
+*/
+func EscapeCodeBlock(text string) string {
+	for strings.Contains(text, "``") {
+		text = strings.ReplaceAll(text, "``", "`"+zeroWidthSpace+"`")
+	}
+	return text
+}
+
+/*
+EscapeInlineCode makes text safe to place inside `inline code`. Any backtick would end the code, and backslashes do not
+escape inside it, so backticks are replaced with the look-alike ˋ (U+02CB)
+*/
+func EscapeInlineCode(text string) string {
+	return strings.ReplaceAll(text, "`", "ˋ")
+}
+
+/*
+Escape makes text safe to place in the context c (see the Escape, EscapeCodeBlock and EscapeInlineCode functions)
+*/
+func (c Context) Escape(text string) string {
+	switch c {
+	case CodeBlockContext:
+		return EscapeCodeBlock(text)
+	case InlineCodeContext:
+		return EscapeInlineCode(text)
+	default:
+		return Escape(text)
+	}
+}
+
+/*
+After returns the context found at the end of markdown, given that markdown starts in the context c. It follows code
+fences and backticks, so
+
+	TextContext.After("see ```go\n") == CodeBlockContext
+*/
+func (c Context) After(markdown string) Context {
+	for len(markdown) > 0 {
+		switch {
+		case c != InlineCodeContext && strings.HasPrefix(markdown, codeFence):
+			if c == CodeBlockContext {
+				c = TextContext
+			} else {
+				c = CodeBlockContext
+			}
+			markdown = markdown[len(codeFence):]
+			continue
+		case c == TextContext && strings.HasPrefix(markdown, `\`) && len(markdown) > 1:
+			markdown = markdown[2:] // An escaped character never changes the context
+			continue
+		case c == TextContext && markdown[0] == '`':
+			c = InlineCodeContext
+		case c == InlineCodeContext && markdown[0] == '`':
+			c = TextContext
+		}
+		markdown = markdown[1:]
+	}
+	return c
+}
@@ -0,0 +1,40 @@
+package md
+
+import (
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestEscape tests text is escaped for each markdown context
+*/
+func TestEscape(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test formatting and masked links are escaped in text`)
+	t.Cmp(Escape(`*bold* _it_ ~~gone~~ ||spoiler|| > quote \ `+"`code`"),
+		`\*bold\* \_it\_ \~\~gone\~\~ \|\|spoiler\|\| \> quote \\ `+"\\`code\\`")
+	t.Cmp(Escape(`[click](https://example.com)`), `\[click\](https://example.com)`)
+	t.Cmp(Escape(`plain text`), `plain text`)
+
+	t.Log(`2. test mentions are broken up`)
+	t.Cmp(Escape(`@everyone @here`), "@\u200beveryone @\u200bhere")
+	t.Cmp(EscapeMentions(`<@123456789012345678> <@!123456789012345678> <@&123456789012345678> @someone`),
+		"<@\u200b123456789012345678> <@\u200b!123456789012345678> <@\u200b&123456789012345678> @someone")
+
+	t.Log(`3. test code contexts only escape backticks`)
+	t.Cmp(EscapeCodeBlock("a ``` b *c*"), "a `\u200b`\u200b` b *c*")
+	t.Cmp(EscapeCodeBlock("``"), "`\u200b`")
+	t.Cmp(EscapeInlineCode("a ` b _c_"), "a ˋ b _c_")
+	t.Cmp(CodeBlockContext.Escape(`@everyone`), `@everyone`)
+	t.Cmp(TextContext.Escape(`_`), `\_`)
+	t.Cmp(InlineCodeContext.Escape("`"), "ˋ")
+
+	t.Log(`4. test contexts are followed through markdown`)
+	t.Cmp(TextContext.After("see ```go\n"), CodeBlockContext)
+	t.Cmp(CodeBlockContext.After("x := `a`\n```"), TextContext)
+	t.Cmp(TextContext.After("run `"), InlineCodeContext)
+	t.Cmp(InlineCodeContext.After("ls` then"), TextContext)
+	t.Cmp(TextContext.After("\\` not code"), TextContext)
+}
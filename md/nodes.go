@@ -0,0 +1,224 @@
+package md
+
+import (
+	"strings"
+
+	"github.com/Nightmarlin/disgobed/validation"
+)
+
+/*
+Node is a piece of discord markdown. Its String method renders it, so the result can be passed straight to a setter
+
+	desc := md.Concat(md.Bold(md.Text(`Status:`)), md.Text(` `+status), md.CodeBlock(`json`, payload))
+	embed.SetDescription(desc.String())
+
+Nodes escape the text they are given, so composing them never produces broken or unintended formatting
+*/
+type Node interface {
+	String() string
+}
+
+/*
+Text is plain text. It renders escaped (see Escape), so it is always shown exactly as written
+*/
+type Text string
+
+// String renders the text, escaped for discord markdown
+func (t Text) String() string {
+	return Escape(string(t))
+}
+
+// String renders the markdown as it is
+func (r Raw) String() string {
+	return string(r)
+}
+
+// group is a sequence of nodes rendered one after another, with sep between them
+type group struct {
+	nodes []Node
+	sep   string
+}
+
+func (g group) String() string {
+	var b strings.Builder
+	written := false
+	for _, n := range g.nodes {
+		if n == nil {
+			continue
+		}
+		part := n.String()
+		if written {
+			b.WriteString(g.sep)
+			if g.sep == `` && markersMeet(b.String(), part) {
+				b.WriteString(zeroWidthSpace)
+			}
+		}
+		b.WriteString(part)
+		written = true
+	}
+	return b.String()
+}
+
+// markerChars are the characters formatting markers are made of
+const markerChars = `*_~|`
+
+// markersMeet reports whether before ends with an unescaped marker character that after starts with, so writing them
+// next to each other would merge the two markers into one
+func markersMeet(before, after string) bool {
+	if before == `` || after == `` {
+		return false
+	}
+	last := before[len(before)-1]
+	return last == after[0] &&
+		strings.IndexByte(markerChars, last) >= 0 &&
+		!strings.HasSuffix(before, `\`+string(last))
+}
+
+/*
+Concat renders nodes one after another.
+
+Where one node ends with a formatting marker and the next starts with the same character, such as two bold nodes, a
+zero width space is placed between them so discord does not read the markers as one. It counts towards Len
+
+	md.Concat(md.Bold(md.Text(`a`)), md.Bold(md.Text(`b`))).String() == `**a**` + "\u200b" + `**b**`
+*/
+func Concat(nodes ...Node) Node {
+	return group{nodes: nodes}
+}
+
+/*
+Lines renders nodes on separate lines
+*/
+func Lines(nodes ...Node) Node {
+	return group{nodes: nodes, sep: "\n"}
+}
+
+// wrapped is a node whose contents are placed between a pair of formatting markers
+type wrapped struct {
+	marker   string
+	contents Node
+}
+
+// String renders nothing when the contents are empty, as discord would show the markers alone as they are
+func (w wrapped) String() string {
+	contents := w.contents.String()
+	if contents == `` {
+		return ``
+	}
+	return w.marker + contents + w.marker
+}
+
+/*
+Bold renders nodes in **bold**
+*/
+func Bold(nodes ...Node) Node {
+	return wrapped{marker: `**`, contents: Concat(nodes...)}
+}
+
+/*
+Italic renders nodes in *italics*
+*/
+func Italic(nodes ...Node) Node {
+	return wrapped{marker: `*`, contents: Concat(nodes...)}
+}
+
+/*
+Underline renders nodes __underlined__
+*/
+func Underline(nodes ...Node) Node {
+	return wrapped{marker: `__`, contents: Concat(nodes...)}
+}
+
+/*
+Strikethrough renders nodes ~~struck through~~
+*/
+func Strikethrough(nodes ...Node) Node {
+	return wrapped{marker: `~~`, contents: Concat(nodes...)}
+}
+
+/*
+Spoiler renders nodes as a ||spoiler|| that is hidden until clicked
+*/
+func Spoiler(nodes ...Node) Node {
+	return wrapped{marker: `||`, contents: Concat(nodes...)}
+}
+
+// blockQuote is a node whose lines are each quoted
+type blockQuote struct {
+	contents Node
+}
+
+func (q blockQuote) String() string {
+	return `> ` + strings.ReplaceAll(q.contents.String(), "\n", "\n> ")
+}
+
+/*
+BlockQuote renders nodes as a block quote. Every line of the contents is quoted, so it can span several lines
+*/
+func BlockQuote(nodes ...Node) Node {
+	return blockQuote{contents: Concat(nodes...)}
+}
+
+// linkURLEscaper encodes the characters that would end the url of a masked link early
+var linkURLEscaper = strings.NewReplacer(`(`, `%28`, `)`, `%29`, ` `, `%20`, "\n", `%0A`)
+
+// link is a masked link
+type link struct {
+	text Node
+	url  string
+}
+
+func (l link) String() string {
+	return `[` + l.text.String() + `](` + linkURLEscaper.Replace(l.url) + `)`
+}
+
+/*
+Link renders a masked link showing text that opens url. Characters in url that would end the link early are percent
+encoded. Discord only renders masked links in descriptions and field values
+*/
+func Link(text Node, url string) Node {
+	return link{text: text, url: url}
+}
+
+/*
+InlineCode renders code as `inline code`. Backticks in code are replaced as described by EscapeInlineCode
+*/
+func InlineCode(code string) Node {
+	return Raw("`" + EscapeInlineCode(code) + "`")
+}
+
+/*
+CodeBlock renders code in a fenced code block, highlighted as language if it is not empty. code is escaped so it cannot
+close the block early (see EscapeCodeBlock). A language containing anything other than letters, digits and the
+characters +-#._ is left out, as it would be shown as part of the code
+*/
+func CodeBlock(language string, code string) Node {
+	if strings.IndexFunc(language, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(`+-#._`, r))
+	}) >= 0 {
+		language = ``
+	}
+	return Raw(codeFence + language + "\n" + EscapeCodeBlock(code) + "\n" + codeFence)
+}
+
+/*
+Len returns the number of characters node renders to, as discord counts them (see validation.CharCount)
+*/
+func Len(node Node) int {
+	return validation.CharCount(node.String())
+}
+
+/*
+Check renders node and measures it against limit before it is assigned to the property at path, such as
+
+	text, err := md.Check(`description`, desc, validation.DefaultLimits.Description)
+
+It returns the rendered markdown, along with a validation.ErrTooLong error if it does not fit
+*/
+func Check(path string, node Node, limit int) (string, *validation.Error) {
+	text := node.String()
+	if length := validation.CharCount(text); length > limit {
+		return text, validation.NewError(path, validation.ErrTooLong, limit, length)
+	}
+	return text, nil
+}
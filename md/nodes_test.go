@@ -0,0 +1,62 @@
+package md
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestNodes tests markdown is composed safely
+*/
+func TestNodes(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test formatting nodes wrap their contents`)
+	t.Cmp(Bold(Text(`a`)).String(), `**a**`)
+	t.Cmp(Italic(Text(`a`)).String(), `*a*`)
+	t.Cmp(Underline(Text(`a`)).String(), `__a__`)
+	t.Cmp(Strikethrough(Text(`a`)).String(), `~~a~~`)
+	t.Cmp(Spoiler(Text(`a`)).String(), `||a||`)
+	t.Cmp(Bold(Italic(Text(`a`)), Text(` b`)).String(), `***a* b**`)
+
+	t.Log(`2. test text is escaped and raw markdown is not`)
+	t.Cmp(Bold(Text(`*x* @everyone`)).String(), "**\\*x\\* @\u200beveryone**")
+	t.Cmp(Concat(Raw(`*x*`), nil, Text(`_`)).String(), `*x*\_`)
+	t.Cmp(Lines(Text(`a`), Text(`b`)).String(), "a\nb")
+
+	t.Log(`3. test quotes, links and code`)
+	t.Cmp(BlockQuote(Lines(Text(`a`), Text(`b`))).String(), "> a\n> b")
+	t.Cmp(Link(Text(`[docs]`), `https://example.com/a (b)`).String(), `[\[docs\]](https://example.com/a%20%28b%29)`)
+	t.Cmp(InlineCode("x := `y`").String(), "`x := ˋyˋ`")
+	t.Cmp(CodeBlock(`go`, "a := 1\n```").String(), "```go\na := 1\n`\u200b`\u200b`\n```")
+	t.Cmp(CodeBlock("go\nevil", `a`).String(), "```\na\n```")
+	t.Cmp(CodeBlock(`c++`, `a`).String(), "```c++\na\n```")
+
+	t.Log(`4. test the rendered length is checked against limits`)
+	doc := Concat(Bold(Text(`title`)), Text(strings.Repeat(`a`, 10)))
+	t.Cmp(Len(doc), 19)
+	text, err := Check(`description`, doc, 19)
+	t.Cmp(text, `**title**aaaaaaaaaa`)
+	t.Cmp(err, td.Nil())
+	_, err = Check(`fields[0].value`, doc, 10)
+	t.Cmp(err, validation.NewError(`fields[0].value`, validation.ErrTooLong, 10, 19))
+
+	t.Log(`5. test adjacent formatting markers are kept apart`)
+	t.Cmp(Concat(Bold(Text(`a`)), Bold(Text(`b`))).String(), "**a**\u200b**b**")
+	t.Cmp(Concat(Italic(Text(`a`)), Italic(Text(`b`))).String(), "*a*\u200b*b*")
+	t.Cmp(Concat(Underline(Text(`a`)), Underline(Text(`b`))).String(), "__a__\u200b__b__")
+	t.Cmp(Concat(Spoiler(Text(`a`)), Bold(), Spoiler(Text(`b`))).String(), "||a||\u200b||b||")
+	t.Cmp(Concat(Bold(Text(`a`)), Italic(Text(`b`))).String(), "**a**\u200b*b*")
+	t.Cmp(Concat(Bold(Text(`a`)), Underline(Text(`b`))).String(), `**a**__b__`)
+	t.Cmp(Concat(Text(`a*`), Italic(Text(`b`))).String(), `a\**b*`)
+	t.Cmp(Lines(Bold(Text(`a`)), Bold(Text(`b`))).String(), "**a**\n**b**")
+	t.Cmp(Len(Concat(Bold(Text(`a`)), Bold(Text(`b`)))), 11)
+
+	t.Log(`6. test empty formatting renders nothing`)
+	t.Cmp(Bold().String(), ``)
+	t.Cmp(Italic(Text(``)).String(), ``)
+	t.Cmp(Concat(Text(`a`), Strikethrough(), Text(`b`)).String(), `ab`)
+}
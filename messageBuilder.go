@@ -0,0 +1,315 @@
+package disgobed
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+)
+
+/*
+maxParamsEmbeds is the number of embeds a disgord.CreateMessageParams can carry. Discord accepts up to 10 embeds per
+message, but disgord v0.17.3 only sends one
+*/
+const maxParamsEmbeds = 1
+
+/*
+MessageBuilder wraps the disgord.CreateMessageParams type and adds features. Never create it directly, instead use the
+NewMessage function and chain the setters
+
+	params, errs := NewMessage().
+		SetContent(`status report`).
+		AddEmbed(NewEmbed().SetTitle(`all good`)).
+		AddFile(`graph.png`, file).
+		Finalize()
+
+Embeds and files are checked together when the message is finalized, so `attachment://` urls are matched against the
+files that were added.
+Discord also supports replies and allowed mentions, but disgord v0.17.3 has no fields for either on
+disgord.CreateMessageParams, so the MessageBuilder cannot set them
+*/
+type MessageBuilder struct {
+	*disgord.CreateMessageParams
+	Errors *[]error
+
+	opts        builderOptions
+	attachments *Attachments
+}
+
+/*
+NewMessage creates and returns an empty message
+*/
+func NewMessage() *MessageBuilder {
+	return &MessageBuilder{
+		CreateMessageParams: &disgord.CreateMessageParams{},
+		Errors:              nil,
+	}
+}
+
+/*
+Finalize strips away the extra functions and returns the wrapped type, ready to be passed to
+disgord.Client.CreateMessage. The content, embed and files are validated together (see validation.ValidateEmbeds), and
+any problems at least as severe as the FailOn threshold are returned along with the errors recorded by the setters.
+Files in the registry given to SetAttachments are added to the message, and any the embed does not use are reported.
+Registry files named the same as a file added with AddFile are not added and are reported as errors.
+Finalize will also purge the error cache!
+*/
+func (m *MessageBuilder) Finalize() (*disgord.CreateMessageParams, *[]error) {
+	defer func(m *MessageBuilder) { m.Errors = nil }(m)
+
+	var embeds []*disgord.Embed
+	if m.Embed != nil {
+		embeds = append(embeds, m.Embed)
+	}
+
+	errs := m.Errors
+	if m.attachments != nil {
+		files, attachmentErrs := m.attachments.Finalize(embeds...)
+		m.attachments = nil
+		errs = validation.MergeErrors(errs, attachmentErrs)
+		for i, f := range files {
+			if m.hasFile(f.FileName) {
+				path := fmt.Sprintf(`attachments[%d]`, i)
+				errs = validation.MergeErrors(errs, &[]error{
+					validation.NewError(path, validation.ErrDuplicateFileName, nil, f.FileName),
+				})
+			} else {
+				m.Files = append(m.Files, f)
+			}
+		}
+	}
+
+	msg := &disgord.Message{Content: m.Content}
+	for _, f := range m.Files {
+		msg.Attachments = append(msg.Attachments, &disgord.Attachment{Filename: f.FileName})
+	}
+
+	found := validation.ValidateEmbedsWithLimits(embeds, msg, m.opts.limits(), m.opts.ruleSet())
+	errs = validation.MergeErrors(errs, withoutRecorded(errs, found))
+	return m.CreateMessageParams, validation.FilterSeverity(errs, m.opts.failOn)
+}
+
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. Embeds added afterwards
+panic on their recorded errors too. It then returns the pointer to the MessageBuilder
+*/
+func (m *MessageBuilder) Strict() *MessageBuilder {
+	m.opts.strict = true
+	return m
+}
+
+/*
+SetLimits makes the MessageBuilder check values against the given limits profile instead of validation.DefaultLimits,
+then returns the pointer to the MessageBuilder
+*/
+func (m *MessageBuilder) SetLimits(limits validation.Limits) *MessageBuilder {
+	m.opts.profile = &limits
+	return m
+}
+
+/*
+SetRules makes Finalize check the embeds and message against the given rules instead of validation.DefaultRules, then
+returns the pointer to the MessageBuilder. Embeds created with NewEmbed afterwards use them too
+*/
+func (m *MessageBuilder) SetRules(rules *validation.RuleSet) *MessageBuilder {
+	m.opts.rules = rules
+	return m
+}
+
+/*
+FailOn sets the least severe kind of problem that Finalize reports. The default, validation.SeverityError, only
+reports problems discord will reject. It then returns the pointer to the MessageBuilder
+*/
+func (m *MessageBuilder) FailOn(threshold validation.Severity) *MessageBuilder {
+	m.opts.failOn = threshold
+	return m
+}
+
+/*
+EnableTruncation makes the MessageBuilder cut content that is too long down to its limit, appending ellipsis to mark
+the cut, instead of dropping it. Each cut is recorded as a warning. Pass validation.DefaultEllipsis for the standard
+marker. It then returns the pointer to the MessageBuilder
+*/
+func (m *MessageBuilder) EnableTruncation(ellipsis string) *MessageBuilder {
+	m.opts.truncate = true
+	m.opts.ellipsis = ellipsis
+	return m
+}
+
+/*
+DisableTruncation restores the default behaviour of dropping content that is too long, then returns the pointer to
+the MessageBuilder
+*/
+func (m *MessageBuilder) DisableTruncation() *MessageBuilder {
+	m.opts.truncate = false
+	return m
+}
+
+/*
+addError records a validation error for the property at path in the error slice stored in MessageBuilder. If the
+pointer is nil a new error slice is created. This function takes the same inputs as validation.NewError
+*/
+func (m *MessageBuilder) addError(path string, code error, limit interface{}, value interface{}) {
+	if m.Errors == nil {
+		m.Errors = &[]error{}
+	}
+	err := validation.NewError(path, code, limit, value)
+	m.opts.check(err)
+	*m.Errors = append(*m.Errors, err)
+}
+
+/*
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
+*/
+func (m *MessageBuilder) addRawError(err error) {
+	m.opts.check(err)
+	if m.Errors == nil {
+		m.Errors = &[]error{}
+	}
+	*m.Errors = append(*m.Errors, err)
+}
+
+/*
+addAllRawErrors takes a pre-existing error slice from an embed and adds it to the stored slice, placing the paths of
+any validation errors beneath prefix. If the pointer is nil a new error slice is created.
+*/
+func (m *MessageBuilder) addAllRawErrors(prefix string, errs *[]error) {
+	errs = validation.PrefixErrors(prefix, errs)
+	if errs == nil {
+		return
+	}
+	for _, err := range *errs {
+		m.addRawError(err)
+	}
+}
+
+/*
+NewEmbed creates and returns an empty embed that inherits the message's strict mode, truncation and limits settings
+*/
+func (m *MessageBuilder) NewEmbed() *EmbedBuilder {
+	res := NewEmbed()
+	res.opts = m.opts
+	return res
+}
+
+/*
+SetContent sets the text of the message then returns the pointer to the MessageBuilder. The discord API limits message
+content to 2000 characters, so this function will do nothing if validation.CharCount(content) > 2000. If truncation is
+enabled, content that is too long is cut down instead
+(This function fails silently)
+*/
+func (m *MessageBuilder) SetContent(content string) *MessageBuilder {
+	limit := m.opts.limits().MessageContent
+	content, warning := m.opts.fit(`content`, content, limit)
+	if warning != nil {
+		m.addRawError(warning)
+	}
+	if length := validation.CharCount(content); length <= limit {
+		m.Content = content
+	} else {
+		m.addError(`content`, validation.ErrTooLong, limit, length)
+	}
+	return m
+}
+
+/*
+SetTTS sets whether the message is read aloud by text to speech then returns the pointer to the MessageBuilder
+*/
+func (m *MessageBuilder) SetTTS(tts bool) *MessageBuilder {
+	m.Tts = tts
+	return m
+}
+
+/*
+SetNonce sets the nonce used to check the message was sent then returns the pointer to the MessageBuilder
+*/
+func (m *MessageBuilder) SetNonce(nonce string) *MessageBuilder {
+	m.Nonce = nonce
+	return m
+}
+
+/*
+AddEmbed takes an EmbedBuilder and adds its embed to the message, then returns the pointer to the MessageBuilder.
+Note that the EmbedBuilder is `Finalize`d once added, and a copy is stored, so changing it afterwards does not change
+the message. All errors are propagated to the message beneath `embeds[i]`. disgord v0.17.3 can only send one embed
+per message, so this function will not add an embed if the message already has one
+(This function fails silently)
+*/
+func (m *MessageBuilder) AddEmbed(embed *EmbedBuilder) *MessageBuilder {
+	res, errs := embed.finalize()
+	m.addAllRawErrors(fmt.Sprintf(`embeds[%d]`, m.embedCount()), errs)
+	return m.AddRawEmbed(copyEmbed(res))
+}
+
+/*
+AddRawEmbed takes a disgord.Embed and adds it to the message, then returns the pointer to the MessageBuilder.
+disgord v0.17.3 can only send one embed per message, so this function will not add an embed if the message already
+has one
+(This function fails silently)
+*/
+func (m *MessageBuilder) AddRawEmbed(embed *disgord.Embed) *MessageBuilder {
+	if count := m.embedCount(); count >= maxParamsEmbeds {
+		m.addError(`embeds`, validation.ErrTooMany, maxParamsEmbeds, count+1)
+	} else {
+		m.Embed = embed
+	}
+	return m
+}
+
+/*
+AddFile attaches the contents of r to the message under the given file name, then returns the pointer to the
+MessageBuilder. Embeds can show the file with an `attachment://` url naming it. The file is not added if name is
+empty or already used by another file
+(This function fails silently)
+*/
+func (m *MessageBuilder) AddFile(name string, r io.Reader) *MessageBuilder {
+	return m.AddRawFile(disgord.CreateMessageFileParams{Reader: r, FileName: name})
+}
+
+/*
+AddRawFile takes a disgord.CreateMessageFileParams and attaches it to the message, then returns the pointer to the
+MessageBuilder. The file is not added if it has no name, or if the message already has a file with the same name
+(This function fails silently)
+*/
+func (m *MessageBuilder) AddRawFile(file disgord.CreateMessageFileParams) *MessageBuilder {
+	path := fmt.Sprintf(`files[%d].name`, len(m.Files))
+	if file.FileName == `` {
+		m.addError(path, validation.ErrEmpty, nil, file.FileName)
+	} else if m.hasFile(file.FileName) {
+		m.addError(path, validation.ErrDuplicateFileName, nil, file.FileName)
+	} else {
+		m.Files = append(m.Files, file)
+	}
+	return m
+}
+
+/*
+SetAttachments uploads the files in the given registry with the message, then returns the pointer to the
+MessageBuilder. The files are added when the message is finalized, so attachments can still be added to the registry
+until then. Registered files that the embed does not reference are reported as warnings
+*/
+func (m *MessageBuilder) SetAttachments(attachments *Attachments) *MessageBuilder {
+	m.attachments = attachments
+	return m
+}
+
+// embedCount returns the number of embeds attached to the message
+func (m *MessageBuilder) embedCount() int {
+	if m.Embed == nil {
+		return 0
+	}
+	return 1
+}
+
+// hasFile checks whether a file named name has been added to the message
+func (m *MessageBuilder) hasFile(name string) bool {
+	for _, f := range m.Files {
+		if f.FileName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package disgobed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestNewMessage tests the MessageBuilder assembles and validates disgord.CreateMessageParams
+*/
+func TestNewMessage(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test a valid message`)
+	file := strings.NewReader(`png`)
+	gotParams, gotErrors := NewMessage().
+		SetContent(`status report`).
+		SetTTS(true).
+		AddEmbed(NewEmbed().SetTitle(`all good`).SetImage(NewImage().SetURL(`attachment://graph.png`))).
+		AddFile(`graph.png`, file).
+		Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotParams, &disgord.CreateMessageParams{
+		Content: `status report`,
+		Tts:     true,
+		Embed: &disgord.Embed{
+			Title: `all good`,
+			Image: &disgord.EmbedImage{URL: `attachment://graph.png`},
+		},
+		Files: []disgord.CreateMessageFileParams{{Reader: file, FileName: `graph.png`}},
+	})
+
+	t.Log(`2. test embed errors are collected and the message is checked as a whole`)
+	gotParams, gotErrors = NewMessage().
+		AddEmbed(NewEmbed().SetColor(-1).SetThumbnail(NewThumbnail().SetURL(`attachment://missing.png`))).
+		AddEmbed(NewEmbed().SetTitle(`second`)).
+		AddFile(``, strings.NewReader(``)).
+		Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`embeds[0].color`, validation.ErrOutOfRange, validation.MaxColorValue, -1),
+		validation.NewError(`embeds`, validation.ErrTooMany, 1, 2),
+		validation.NewError(`files[0].name`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`embeds[0].thumbnail.url`, validation.ErrMissingAttachment, nil, `attachment://missing.png`),
+	})
+	t.Cmp(gotParams.Embed.Title, ``)
+	t.Cmp(gotParams.Files, td.Nil())
+
+	t.Log(`3. test content limits and warning thresholds`)
+	gotParams, gotErrors = NewMessage().SetContent(strings.Repeat(`a`, validation.MaxMessageContentCharLimit+1)).Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`content`, validation.ErrTooLong, validation.MaxMessageContentCharLimit, validation.MaxMessageContentCharLimit+1),
+	})
+	t.Cmp(gotParams.Content, ``)
+
+	_, gotErrors = NewMessage().AddEmbed(NewEmbed().SetURL(`http://example.com`)).Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	_, gotErrors = NewMessage().FailOn(validation.SeverityWarning).AddEmbed(NewEmbed().SetURL(`http://example.com`)).Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewWarning(`embeds[0].url`, validation.ErrInsecureURL, nil, `http://example.com`),
+	})
+
+	t.Log(`4. test the message rules can be changed`)
+	_, gotErrors = NewMessage().
+		SetRules(validation.BuiltinRules().Disable(validation.RuleAttachments)).
+		AddEmbed(NewEmbed().SetImage(NewImage().SetURL(`attachment://missing.png`))).
+		Finalize()
+	t.Cmp(gotErrors, td.Nil())
+}
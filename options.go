@@ -0,0 +1,78 @@
+package disgobed
+
+import (
+	"github.com/Nightmarlin/disgobed/validation"
+)
+
+/*
+builderOptions holds the behaviour shared by the builders in this package. The zero value gives the default behaviour,
+where values that break a limit are dropped and an error is recorded
+*/
+type builderOptions struct {
+	// truncate makes setters cut values down to their limit instead of dropping them
+	truncate bool
+
+	// ellipsis is appended to values that have been truncated
+	ellipsis string
+
+	// strict makes the first error-level problem panic at the setter that caused it
+	strict bool
+
+	// profile overrides validation.DefaultLimits when set
+	profile *validation.Limits
+
+	// rules overrides validation.DefaultRules when set
+	rules *validation.RuleSet
+
+	// failOn is the least severe kind of problem that Validate reports. The zero value is validation.SeverityError
+	failOn validation.Severity
+
+	// immutable makes setters change and return a copy of the builder instead of the builder itself
+	immutable bool
+}
+
+/*
+limits returns the limits profile the builder should check against
+*/
+func (o builderOptions) limits() validation.Limits {
+	if o.profile == nil {
+		return validation.DefaultLimits
+	}
+	return *o.profile
+}
+
+/*
+ruleSet returns the rules the builder should validate against
+*/
+func (o builderOptions) ruleSet() *validation.RuleSet {
+	if o.rules == nil {
+		return validation.DefaultRules
+	}
+	return o.rules
+}
+
+/*
+check panics with err if strict mode is enabled and err is error-level, stopping a chain of setters at the call that
+caused the problem. Warnings never panic
+*/
+func (o builderOptions) check(err error) {
+	if o.strict && validation.HasErrors(&[]error{err}) {
+		panic(err)
+	}
+}
+
+/*
+fit applies the truncation policy to the value of the property at path. If truncation is enabled and value is longer
+than limit, the truncated value is returned along with a warning describing the cut. Otherwise value is returned
+unchanged along with a nil warning
+*/
+func (o builderOptions) fit(path string, value string, limit int) (string, *validation.Error) {
+	if !o.truncate {
+		return value, nil
+	}
+	length := validation.CharCount(value)
+	if length <= limit {
+		return value, nil
+	}
+	return validation.Truncate(value, limit, o.ellipsis), validation.NewWarning(path, validation.ErrTruncated, limit, length)
+}
@@ -0,0 +1,315 @@
+package disgobed
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+)
+
+/*
+DefaultPageFormat is the format used by Paginator.ShowPageNumbers for the page number added to each footer. It is
+given the page number and the page count
+*/
+const DefaultPageFormat = `Page %d/%d`
+
+// pageFooterSeparator is placed between the template's footer text and the page number
+const pageFooterSeparator = ` • `
+
+/*
+Paginator splits content that is too long for one embed across several embeds that share a template. Never create it
+directly, instead use the NewPaginator function
+
+	pages, errs := NewPaginator(NewEmbed().SetTitle(`Search results`).SetColor(0x00ff00)).
+		ShowPageNumbers(DefaultPageFormat).
+		PaginateEntries(results)
+
+Each page is a copy of the template with the content in its description. Content is broken between entries (or lines
+for PaginateText) wherever possible, and markdown code blocks that span a page break are closed at the end of one page
+and reopened at the start of the next
+*/
+type Paginator struct {
+	template *disgord.Embed
+	errs     *[]error
+	opts     builderOptions
+
+	// pageFormat is the format of the page number added to each footer, or empty for no page numbers
+	pageFormat string
+}
+
+/*
+NewPaginator creates a Paginator that uses template for every page. The template is finalized, and any errors it
+recorded are returned by every call to PaginateText and PaginateEntries. The template's description is replaced on each
+page. Pages are checked with the template's rules (see EmbedBuilder.SetRules), and only problems at least as severe as
+its FailOn threshold are returned
+*/
+func NewPaginator(template *EmbedBuilder) *Paginator {
+	embed, errs := template.finalize()
+	return &Paginator{
+		template: copyEmbed(embed),
+		errs:     errs,
+		opts:     template.opts,
+	}
+}
+
+/*
+ShowPageNumbers adds the page number to the footer of each page using format, which is given the page number and the
+page count (see DefaultPageFormat). If the template has footer text the page number is added after it. It then returns
+the pointer to the Paginator
+*/
+func (p *Paginator) ShowPageNumbers(format string) *Paginator {
+	p.pageFormat = format
+	return p
+}
+
+/*
+PaginateText splits text across as many pages as it needs, breaking between lines wherever possible. Lines too long to
+fit on a page by themselves are split between characters, without splitting combined characters such as emoji
+sequences. It returns the pages along with the template's errors and any problems found validating the pages, whose
+paths are placed beneath `pages[i]`
+*/
+func (p *Paginator) PaginateText(text string) ([]*disgord.Embed, *[]error) {
+	return p.paginate(strings.Split(text, "\n"))
+}
+
+/*
+PaginateEntries places entries on as many pages as they need, one entry per line, never splitting an entry across
+pages unless it is too long to fit on a page by itself. It returns the pages along with the template's errors and any
+problems found validating the pages, whose paths are placed beneath `pages[i]`
+*/
+func (p *Paginator) PaginateEntries(entries []string) ([]*disgord.Embed, *[]error) {
+	return p.paginate(entries)
+}
+
+/*
+paginate lays units out onto pages and builds the page embeds. The space reserved for page numbers depends on the
+number of pages, so the layout is repeated until the reserved space is enough
+*/
+func (p *Paginator) paginate(units []string) ([]*disgord.Embed, *[]error) {
+	var descriptions []string
+	for digits := 1; ; digits++ {
+		capacity := p.capacity(digits)
+		if capacity <= validation.CharCount(codeFence)*2 {
+			limit := p.opts.limits().Total
+			errs := &[]error{validation.NewError(`description`, validation.ErrTotalTooLong, limit, limit-capacity)}
+			return nil, validation.FilterSeverity(validation.MergeErrors(p.errs, errs), p.opts.failOn)
+		}
+
+		w := &pageWriter{capacity: capacity}
+		for _, unit := range units {
+			w.add(unit)
+		}
+		descriptions = w.finish()
+
+		if len(fmt.Sprint(len(descriptions))) <= digits {
+			break
+		}
+	}
+
+	var pages []*disgord.Embed
+	var found []*[]error
+	for i, desc := range descriptions {
+		page := copyEmbed(p.template)
+		page.Description = desc
+		if p.pageFormat != `` {
+			page.Footer = p.pageFooter(i+1, len(descriptions))
+		}
+		pages = append(pages, page)
+		// Problems with the template are reported once rather than once per page
+		pageErrs := withoutRecorded(p.errs, p.opts.ruleSet().Validate(page, nil, p.opts.limits()))
+		found = append(found, validation.PrefixErrors(fmt.Sprintf(`pages[%d]`, i), pageErrs))
+	}
+	errs := validation.MergeErrors(append([]*[]error{p.errs}, found...)...)
+	return pages, validation.FilterSeverity(errs, p.opts.failOn)
+}
+
+/*
+capacity returns the number of characters available to the description of each page, leaving room for page numbers
+of up to digits digits
+*/
+func (p *Paginator) capacity(digits int) int {
+	limits := p.opts.limits()
+	used := validation.TotalCharacterCount(p.template) - validation.CharCount(p.template.Description)
+	if p.pageFormat != `` {
+		widest := 0
+		for i := 0; i < digits; i++ {
+			widest = widest*10 + 9
+		}
+		used += validation.CharCount(p.pageFooter(widest, widest).Text) - footerCharCount(p.template.Footer)
+	}
+	if available := limits.Total - used; available < limits.Description {
+		return available
+	}
+	return limits.Description
+}
+
+/*
+pageFooter returns a copy of the template's footer with the page number added to its text
+*/
+func (p *Paginator) pageFooter(page int, count int) *disgord.EmbedFooter {
+	footer := &disgord.EmbedFooter{}
+	if p.template.Footer != nil {
+		footer = p.template.Footer.DeepCopy().(*disgord.EmbedFooter)
+	}
+	number := fmt.Sprintf(p.pageFormat, page, count)
+	if footer.Text == `` {
+		footer.Text = number
+	} else {
+		footer.Text += pageFooterSeparator + number
+	}
+	return footer
+}
+
+// codeFence opens and closes a markdown code block
+const codeFence = "```"
+
+/*
+pageWriter lays lines of text out onto pages of at most capacity characters, keeping code blocks balanced on every page
+*/
+type pageWriter struct {
+	capacity int
+	pages    []string
+
+	// lines holds the lines of the current page
+	lines []string
+
+	// length is the number of characters in the current page, including the newlines between its lines
+	length int
+
+	// fence is the line that opened the code block the current page ends inside, or empty if it is not in one
+	fence string
+
+	// reopened is true while the current page holds nothing but the fence reopening a code block
+	reopened bool
+}
+
+/*
+add places unit on the current page, starting a new page first if it does not fit. Units too long for an empty page are
+broken into lines, and lines into characters
+*/
+func (w *pageWriter) add(unit string) {
+	if w.fits(unit) {
+		w.write(unit)
+		return
+	}
+	if w.hasContent() {
+		w.flush()
+		if w.fits(unit) {
+			w.write(unit)
+			return
+		}
+	}
+
+	if lines := strings.Split(unit, "\n"); len(lines) > 1 {
+		for _, line := range lines {
+			w.add(line)
+		}
+		return
+	}
+
+	// A single line that cannot fit on an empty page is split between characters, keeping combined characters whole
+	for unit != `` {
+		available := w.capacity - w.length - w.closingCost(w.fence)
+		if len(w.lines) > 0 {
+			available--
+		}
+		if available <= 0 {
+			if w.hasContent() {
+				w.flush()
+			} else { // The reopening fence alone fills the page, so the block cannot be carried over
+				w.lines, w.length, w.fence, w.reopened = nil, 0, ``, false
+			}
+			continue
+		}
+		var part string
+		part, unit = validation.Cut(unit, available)
+		w.write(part)
+		if unit != `` {
+			w.flush()
+		}
+	}
+}
+
+/*
+fits checks whether unit can be added to the current page while leaving room to close any code block it ends inside
+*/
+func (w *pageWriter) fits(unit string) bool {
+	cost := validation.CharCount(unit)
+	if len(w.lines) > 0 {
+		cost++
+	}
+	return w.length+cost+w.closingCost(trackFence(w.fence, unit)) <= w.capacity
+}
+
+/*
+closingCost returns the number of characters needed to close the code block opened by fence, if there is one
+*/
+func (w *pageWriter) closingCost(fence string) int {
+	if fence == `` {
+		return 0
+	}
+	return validation.CharCount(codeFence) + 1
+}
+
+// write adds unit to the current page
+func (w *pageWriter) write(unit string) {
+	if len(w.lines) > 0 {
+		w.length++
+	}
+	w.lines = append(w.lines, unit)
+	w.length += validation.CharCount(unit)
+	w.fence = trackFence(w.fence, unit)
+	w.reopened = false
+}
+
+// hasContent checks whether the current page holds anything other than a reopened code block fence
+func (w *pageWriter) hasContent() bool {
+	return len(w.lines) > 0 && !w.reopened
+}
+
+/*
+flush ends the current page, closing any open code block, and starts a new one that reopens it
+*/
+func (w *pageWriter) flush() {
+	if w.fence != `` {
+		w.lines = append(w.lines, codeFence)
+	}
+	w.pages = append(w.pages, strings.Join(w.lines, "\n"))
+
+	w.lines, w.length = nil, 0
+	if w.fence != `` {
+		w.lines = []string{w.fence}
+		w.length = validation.CharCount(w.fence)
+		w.reopened = true
+	}
+}
+
+/*
+finish ends the last page and returns the text of every page. Content that ends inside a code block is left as it was
+given
+*/
+func (w *pageWriter) finish() []string {
+	if w.hasContent() {
+		w.pages = append(w.pages, strings.Join(w.lines, "\n"))
+	}
+	return w.pages
+}
+
+/*
+trackFence returns the fence line of the code block that is open after text, given the fence of the block open
+before it (or an empty string if none was)
+*/
+func trackFence(fence string, text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Count(line, codeFence)%2 == 0 {
+			continue
+		}
+		if fence == `` {
+			fence = strings.TrimSpace(line[strings.LastIndex(line, codeFence):])
+		} else {
+			fence = ``
+		}
+	}
+	return fence
+}
@@ -0,0 +1,104 @@
+package disgobed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestPaginator tests that long content is split across valid pages
+*/
+func TestPaginator(tt *testing.T) {
+	t := td.NewT(tt)
+
+	template := func() *EmbedBuilder {
+		return NewEmbed().SetTitle(`Results`).SetColor(0x00ff00).SetAuthor(NewAuthor().SetName(`bot`))
+	}
+
+	t.Log(`1. test short content fits on one page`)
+	pages, errs := NewPaginator(template()).PaginateText("one\ntwo")
+	t.Cmp(errs, td.Nil())
+	t.Cmp(pages, td.Len(1))
+	t.Cmp(pages[0].Description, "one\ntwo")
+	t.Cmp(pages[0].Title, `Results`)
+	t.Cmp(pages[0].Author.Name, `bot`)
+	t.Cmp(pages[0].Footer, td.Nil())
+
+	t.Log(`2. test entries are never split and pages carry numbers`)
+	var entries []string
+	for i := 0; i < 100; i++ {
+		entries = append(entries, fmt.Sprintf(`%03d %v`, i, strings.Repeat(`x`, 96)))
+	}
+	pages, errs = NewPaginator(template().SetFooter(NewFooter().SetText(`search`))).
+		ShowPageNumbers(DefaultPageFormat).
+		PaginateEntries(entries)
+	t.Cmp(errs, td.Nil())
+	t.Cmp(pages, td.Len(5))
+	var joined []string
+	for i, page := range pages {
+		t.Cmp(validation.CharCount(page.Description), td.Lte(validation.UpperCharLimit))
+		t.Cmp(page.Footer.Text, fmt.Sprintf(`search • Page %d/5`, i+1))
+		joined = append(joined, page.Description)
+	}
+	t.Cmp(strings.Join(joined, "\n"), strings.Join(entries, "\n"))
+
+	t.Log(`3. test code blocks are balanced on every page`)
+	code := "Log:\n```go\n" + strings.Repeat(strings.Repeat(`y`, 99)+"\n", 50) + "```\nend"
+	pages, errs = NewPaginator(template()).PaginateText(code)
+	t.Cmp(errs, td.Nil())
+	t.Cmp(pages, td.Len(3))
+	for i, page := range pages {
+		t.Cmp(strings.Count(page.Description, "```")%2, 0)
+		if i > 0 {
+			t.True(strings.HasPrefix(page.Description, "```go\n"))
+		}
+		if i < len(pages)-1 {
+			t.True(strings.HasSuffix(page.Description, "\n```"))
+		}
+	}
+
+	t.Log(`4. test over long lines are split between characters and the total budget is respected`)
+	long := strings.Repeat(`z`, 3*validation.UpperCharLimit)
+	pages, errs = NewPaginator(template().SetDescription(`replaced`)).PaginateText(long)
+	t.Cmp(errs, td.Nil())
+	t.Cmp(pages, td.Len(3))
+	t.Cmp(pages[0].Description, strings.Repeat(`z`, validation.UpperCharLimit))
+
+	pages, errs = NewPaginator(NewEmbed().SetLimits(validation.CurrentLimits).SetTitle(`t`)).PaginateText(long)
+	t.Cmp(errs, td.Nil())
+	t.Cmp(pages, td.Len(2))
+	t.Cmp(validation.CharCount(pages[0].Description), 4096)
+
+	family := "\U0001F469\u200d\U0001F469\u200d\U0001F467"
+	pages, errs = NewPaginator(NewEmbed()).PaginateText(strings.Repeat(`z`, validation.UpperCharLimit-2) + family)
+	t.Cmp(errs, td.Nil())
+	t.Cmp(pages, td.Len(2))
+	t.Cmp(pages[1].Description, family)
+
+	t.Log(`5. test template errors are reported`)
+	_, errs = NewPaginator(NewEmbed().SetColor(-1)).PaginateText(`a`)
+	t.Cmp(errs, &[]error{validation.NewError(`color`, validation.ErrOutOfRange, validation.MaxColorValue, -1)})
+
+	t.Log(`6. test template warnings are reported once, and only when asked for`)
+	twoPages := strings.Repeat(`z`, validation.UpperCharLimit+1)
+	_, errs = NewPaginator(template().SetURL(`http://example.com`)).PaginateText(twoPages)
+	t.Cmp(errs, td.Nil())
+	_, errs = NewPaginator(template().SetURL(`http://example.com`).FailOn(validation.SeverityWarning)).PaginateText(twoPages)
+	t.Cmp(errs, &[]error{validation.NewWarning(`url`, validation.ErrInsecureURL, nil, `http://example.com`)})
+
+	t.Log(`7. test pages are checked with the template's rules`)
+	errShort := errors.New(`page too short`)
+	minLength := validation.NewRule(`min-length`, func(target validation.Target) []error {
+		if validation.CharCount(target.Embed.Description) < 10 {
+			return []error{validation.NewError(`description`, errShort, 10, target.Embed.Description)}
+		}
+		return nil
+	})
+	_, errs = NewPaginator(template().SetRules(validation.BuiltinRules().Add(minLength))).PaginateText(`short`)
+	t.Cmp(errs, &[]error{validation.NewError(`pages[0].description`, errShort, 10, `short`)})
+}
@@ -1,28 +1,60 @@
 package disgobed
 
 import (
+	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
 )
 
 /*
 ProviderBuilder wraps the disgord.EmbedProvider type and adds features.
 ProviderBuilder is an esoteric part of the discord API, and is likely to be deprecated in a future version. It is recommended
-you don't use it... Use at your own risk. No ProviderBuilder fields are validated
+you don't use it... Use at your own risk. Only the ProviderBuilder's URL is validated
 */
 type ProviderBuilder struct {
 	*disgord.EmbedProvider
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
-Finalize strips away the extra functions and returns the wrapped type. ProviderBuilder does not perform validation on inputs,
-so Finalize() should always return nil for its errors
+Finalize strips away the extra functions and returns the wrapped type. ProviderBuilder only validates its URL, so
+Finalize() will return nil for its errors unless SetURL was given an invalid url. Finalize will also purge the error
+cache!
+Warnings are dropped, so a nil result means the provider has no problems discord will reject
 */
 func (p *ProviderBuilder) Finalize() (*disgord.EmbedProvider, *[]error) {
 	defer func(p *ProviderBuilder) { p.Errors = nil }(p)
+	return p.EmbedProvider, validation.FilterSeverity(p.Errors, p.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a ProviderBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (p *ProviderBuilder) peek() (*disgord.EmbedProvider, *[]error) {
 	return p.EmbedProvider, p.Errors
 }
 
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the ProviderBuilder
+*/
+func (p *ProviderBuilder) Strict() *ProviderBuilder {
+	p.opts.strict = true
+	return p
+}
+
+/*
+SetLimits makes the ProviderBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the ProviderBuilder
+*/
+func (p *ProviderBuilder) SetLimits(limits validation.Limits) *ProviderBuilder {
+	p.opts.profile = &limits
+	return p
+}
+
 /*
 NewProvider creates and returns a pointer to an empty provider struct
 */
@@ -34,10 +66,32 @@ func NewProvider() *ProviderBuilder {
 }
 
 /*
-SetURL sets the provider's URL field and returns the pointer to the ProviderBuilder
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
+*/
+func (p *ProviderBuilder) addRawError(err error) {
+	p.opts.check(err)
+	if p.Errors == nil {
+		p.Errors = &[]error{}
+	}
+	*p.Errors = append(*p.Errors, err)
+}
+
+/*
+SetURL sets the provider's URL field and returns the pointer to the ProviderBuilder. The URL must be a valid http:// or
+https:// url (see validation.ValidateURL), otherwise it will not be added
+http:// urls are accepted but recorded as a warning
+(This function fails silently)
 */
 func (p *ProviderBuilder) SetURL(url string) *ProviderBuilder {
-	p.URL = url
+	if err := validation.ValidateURL(`url`, url, false, p.opts.limits()); err == nil {
+		p.URL = url
+		if warning := validation.LintURL(`url`, url); warning != nil {
+			p.addRawError(warning)
+		}
+	} else {
+		p.addRawError(err)
+	}
 	return p
 }
 
@@ -48,3 +102,19 @@ func (p *ProviderBuilder) SetName(name string) *ProviderBuilder {
 	p.Name = name
 	return p
 }
+
+/*
+ClearName removes the provider's name, then returns the pointer to the ProviderBuilder
+*/
+func (p *ProviderBuilder) ClearName() *ProviderBuilder {
+	p.Name = ``
+	return p
+}
+
+/*
+ClearURL removes the provider's url, then returns the pointer to the ProviderBuilder
+*/
+func (p *ProviderBuilder) ClearURL() *ProviderBuilder {
+	p.URL = ``
+	return p
+}
@@ -0,0 +1,446 @@
+package disgobed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+embedSpec is the shape of an embed in the discord API. It is used to read and write embed definitions in JSON, and to
+read them from YAML and TOML files, and is applied to an EmbedBuilder through its setters so that everything read is
+validated
+*/
+type embedSpec struct {
+	Title       string        `json:"title,omitempty" yaml:"title" toml:"title"`
+	Type        string        `json:"type,omitempty" yaml:"type" toml:"type"`
+	Description string        `json:"description,omitempty" yaml:"description" toml:"description"`
+	URL         string        `json:"url,omitempty" yaml:"url" toml:"url"`
+	Timestamp   timestampSpec `json:"timestamp,omitempty" yaml:"timestamp" toml:"timestamp"`
+	Color       *colorSpec    `json:"color,omitempty" yaml:"color" toml:"color"`
+	Footer      *footerSpec   `json:"footer,omitempty" yaml:"footer" toml:"footer"`
+	Image       *imageSpec    `json:"image,omitempty" yaml:"image" toml:"image"`
+	Thumbnail   *imageSpec    `json:"thumbnail,omitempty" yaml:"thumbnail" toml:"thumbnail"`
+	Video       *videoSpec    `json:"video,omitempty" yaml:"video" toml:"video"`
+	Provider    *providerSpec `json:"provider,omitempty" yaml:"provider" toml:"provider"`
+	Author      *authorSpec   `json:"author,omitempty" yaml:"author" toml:"author"`
+	Fields      []*fieldSpec  `json:"fields,omitempty" yaml:"fields" toml:"fields"`
+}
+
+// footerSpec is the shape of an embed footer in the discord API
+type footerSpec struct {
+	Text         string `json:"text" yaml:"text" toml:"text"`
+	IconURL      string `json:"icon_url,omitempty" yaml:"icon_url" toml:"icon_url"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty" yaml:"proxy_icon_url" toml:"proxy_icon_url"`
+}
+
+// imageSpec is the shape of an embed image or thumbnail in the discord API
+type imageSpec struct {
+	URL      string `json:"url,omitempty" yaml:"url" toml:"url"`
+	ProxyURL string `json:"proxy_url,omitempty" yaml:"proxy_url" toml:"proxy_url"`
+	Height   int    `json:"height,omitempty" yaml:"height" toml:"height"`
+	Width    int    `json:"width,omitempty" yaml:"width" toml:"width"`
+}
+
+// videoSpec is the shape of an embed video in the discord API
+type videoSpec struct {
+	URL    string `json:"url,omitempty" yaml:"url" toml:"url"`
+	Height int    `json:"height,omitempty" yaml:"height" toml:"height"`
+	Width  int    `json:"width,omitempty" yaml:"width" toml:"width"`
+}
+
+// providerSpec is the shape of an embed provider in the discord API
+type providerSpec struct {
+	Name string `json:"name,omitempty" yaml:"name" toml:"name"`
+	URL  string `json:"url,omitempty" yaml:"url" toml:"url"`
+}
+
+// authorSpec is the shape of an embed author in the discord API
+type authorSpec struct {
+	Name         string `json:"name,omitempty" yaml:"name" toml:"name"`
+	URL          string `json:"url,omitempty" yaml:"url" toml:"url"`
+	IconURL      string `json:"icon_url,omitempty" yaml:"icon_url" toml:"icon_url"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty" yaml:"proxy_icon_url" toml:"proxy_icon_url"`
+}
+
+// fieldSpec is the shape of an embed field in the discord API
+type fieldSpec struct {
+	Name   string `json:"name" yaml:"name" toml:"name"`
+	Value  string `json:"value" yaml:"value" toml:"value"`
+	Inline bool   `json:"inline,omitempty" yaml:"inline" toml:"inline"`
+}
+
+/*
+timestampSpec is an embed timestamp as read from a file. It holds an RFC 3339 timestamp, or nowTimestamp for the time
+the spec is applied. TOML date-times that are written without quotes are read as RFC 3339 timestamps
+*/
+type timestampSpec string
+
+// nowTimestamp is the timestamp that stands for the time a spec is applied
+const nowTimestamp = `now`
+
+// UnmarshalTOML reads a timestamp from either a TOML date-time or a string
+func (t *timestampSpec) UnmarshalTOML(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		*t = timestampSpec(v.Format(time.RFC3339Nano))
+	case string:
+		*t = timestampSpec(v)
+	default:
+		return fmt.Errorf(`timestamp must be a date-time or a string, not %T`, value)
+	}
+	return nil
+}
+
+/*
+colorSpec is an embed colour as read from a file. It is always written as a number, but can be read from either a
+number or a hex string such as `#ff8800` or `0xff8800`
+*/
+type colorSpec struct {
+	value int
+
+	// hex holds the colour if it was read from a string, and is only parsed when the spec is applied so that invalid
+	// colours are reported as validation errors
+	hex string
+	// isHex is set when the colour was read from a string, so that an empty string is reported rather than read as 0
+	isHex bool
+}
+
+// MarshalJSON writes the colour as a number
+func (c colorSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.value)
+}
+
+// UnmarshalJSON reads the colour from either a number or a hex string
+func (c *colorSpec) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		c.isHex = true
+		return json.Unmarshal(data, &c.hex)
+	}
+	return json.Unmarshal(data, &c.value)
+}
+
+// UnmarshalYAML reads the colour from either a number or a hex string
+func (c *colorSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == `!!str` {
+		c.hex, c.isHex = node.Value, true
+		return nil
+	}
+	return node.Decode(&c.value)
+}
+
+// UnmarshalTOML reads the colour from either an integer or a hex string
+func (c *colorSpec) UnmarshalTOML(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		c.value = int(v)
+	case string:
+		c.hex, c.isHex = v, true
+	default:
+		return fmt.Errorf(`color must be an integer or a string, not %T`, value)
+	}
+	return nil
+}
+
+/*
+resolve returns the colour as a number. The boolean is false if the colour was read from a string that is not hex,
+including an empty one
+*/
+func (c *colorSpec) resolve() (int, bool) {
+	if !c.isHex {
+		return c.value, true
+	}
+	digits := strings.TrimPrefix(c.hex, `#`)
+	if digits == c.hex {
+		digits = strings.TrimPrefix(strings.TrimPrefix(c.hex, `0x`), `0X`)
+	}
+	color, err := strconv.ParseInt(digits, 16, 64)
+	if err != nil || strings.HasPrefix(digits, `-`) || strings.HasPrefix(digits, `+`) {
+		return 0, false
+	}
+	return int(color), true
+}
+
+/*
+specFromEmbed describes embed in the shape of the discord API
+*/
+func specFromEmbed(embed *disgord.Embed) *embedSpec {
+	res := &embedSpec{
+		Title:       embed.Title,
+		Type:        embed.Type,
+		Description: embed.Description,
+		URL:         embed.URL,
+		Footer:      specFromFooter(embed.Footer),
+		Image:       specFromImage(embed.Image),
+		Thumbnail:   specFromThumbnail(embed.Thumbnail),
+		Video:       specFromVideo(embed.Video),
+		Provider:    specFromProvider(embed.Provider),
+		Author:      specFromAuthor(embed.Author),
+	}
+	if embed.Color != 0 {
+		res.Color = &colorSpec{value: embed.Color}
+	}
+	if !embed.Timestamp.IsZero() {
+		res.Timestamp = timestampSpec(embed.Timestamp.Format(time.RFC3339Nano))
+	}
+	for _, f := range embed.Fields {
+		if f != nil {
+			res.Fields = append(res.Fields, specFromField(f))
+		}
+	}
+	return res
+}
+
+// specFromFooter describes footer in the shape of the discord API, or returns nil if footer is nil
+func specFromFooter(footer *disgord.EmbedFooter) *footerSpec {
+	if footer == nil {
+		return nil
+	}
+	return &footerSpec{Text: footer.Text, IconURL: footer.IconURL, ProxyIconURL: footer.ProxyIconURL}
+}
+
+// specFromImage describes image in the shape of the discord API, or returns nil if image is nil
+func specFromImage(image *disgord.EmbedImage) *imageSpec {
+	if image == nil {
+		return nil
+	}
+	return &imageSpec{URL: image.URL, ProxyURL: image.ProxyURL, Height: image.Height, Width: image.Width}
+}
+
+// specFromThumbnail describes thumb in the shape of the discord API, or returns nil if thumb is nil
+func specFromThumbnail(thumb *disgord.EmbedThumbnail) *imageSpec {
+	if thumb == nil {
+		return nil
+	}
+	return &imageSpec{URL: thumb.URL, ProxyURL: thumb.ProxyURL, Height: thumb.Height, Width: thumb.Width}
+}
+
+// specFromVideo describes vid in the shape of the discord API, or returns nil if vid is nil
+func specFromVideo(vid *disgord.EmbedVideo) *videoSpec {
+	if vid == nil {
+		return nil
+	}
+	return &videoSpec{URL: vid.URL, Height: vid.Height, Width: vid.Width}
+}
+
+// specFromProvider describes provider in the shape of the discord API, or returns nil if provider is nil
+func specFromProvider(provider *disgord.EmbedProvider) *providerSpec {
+	if provider == nil {
+		return nil
+	}
+	return &providerSpec{Name: provider.Name, URL: provider.URL}
+}
+
+// specFromAuthor describes author in the shape of the discord API, or returns nil if author is nil
+func specFromAuthor(author *disgord.EmbedAuthor) *authorSpec {
+	if author == nil {
+		return nil
+	}
+	return &authorSpec{Name: author.Name, URL: author.URL, IconURL: author.IconURL, ProxyIconURL: author.ProxyIconURL}
+}
+
+// specFromField describes field in the shape of the discord API, or returns nil if field is nil
+func specFromField(field *disgord.EmbedField) *fieldSpec {
+	if field == nil {
+		return nil
+	}
+	return &fieldSpec{Name: field.Name, Value: field.Value, Inline: field.Inline}
+}
+
+/*
+texts returns a pointer to every text and url property in the spec, keyed by its path
+*/
+func (s *embedSpec) texts() map[string]*string {
+	res := map[string]*string{`title`: &s.Title, `description`: &s.Description, `url`: &s.URL}
+	if f := s.Footer; f != nil {
+		res[`footer.text`], res[`footer.icon_url`], res[`footer.proxy_icon_url`] = &f.Text, &f.IconURL, &f.ProxyIconURL
+	}
+	if i := s.Image; i != nil {
+		res[`image.url`], res[`image.proxy_url`] = &i.URL, &i.ProxyURL
+	}
+	if t := s.Thumbnail; t != nil {
+		res[`thumbnail.url`], res[`thumbnail.proxy_url`] = &t.URL, &t.ProxyURL
+	}
+	if v := s.Video; v != nil {
+		res[`video.url`] = &v.URL
+	}
+	if p := s.Provider; p != nil {
+		res[`provider.name`], res[`provider.url`] = &p.Name, &p.URL
+	}
+	if a := s.Author; a != nil {
+		res[`author.name`], res[`author.url`] = &a.Name, &a.URL
+		res[`author.icon_url`], res[`author.proxy_icon_url`] = &a.IconURL, &a.ProxyIconURL
+	}
+	for i, f := range s.Fields {
+		if f != nil {
+			res[fmt.Sprintf(`fields[%d].name`, i)] = &f.Name
+			res[fmt.Sprintf(`fields[%d].value`, i)] = &f.Value
+		}
+	}
+	return res
+}
+
+/*
+apply sets every property in the spec on e through its setters, so values are validated and errors are recorded
+against the same paths the spec was read from. It then returns the pointer to the EmbedBuilder
+*/
+func (s *embedSpec) apply(e *EmbedBuilder) *EmbedBuilder {
+	if s.Title != `` {
+		e.SetTitle(s.Title)
+	}
+	if s.Type != `` {
+		e.SetType(s.Type)
+	}
+	if s.Description != `` {
+		e.SetDescription(s.Description)
+	}
+	if s.URL != `` {
+		e.SetURL(s.URL)
+	}
+	if s.Timestamp == nowTimestamp {
+		e.SetCurrentTimestamp()
+	} else if s.Timestamp != `` {
+		if t, err := time.Parse(time.RFC3339Nano, string(s.Timestamp)); err == nil {
+			e.SetCustomTimestamp(t)
+		} else {
+			e.addError(`timestamp`, validation.ErrInvalidTimestamp, nil, string(s.Timestamp))
+		}
+	}
+	if s.Color != nil {
+		if color, ok := s.Color.resolve(); !ok {
+			e.addError(`color`, validation.ErrInvalidColor, nil, s.Color.hex)
+		} else if color != 0 {
+			e.SetColor(color)
+		}
+	}
+
+	// Field problems are reported at the index the field has in the source, even once earlier fields are left out
+	for i, f := range s.Fields {
+		path := fmt.Sprintf(`fields[%d]`, i)
+		if f == nil {
+			e.addError(path, validation.ErrEmpty, nil, nil)
+			continue
+		}
+		field, errs := f.apply(e.NewField()).peek()
+		e.addAllRawErrors(path, errs)
+		e.addRawField(path, field)
+	}
+
+	if s.Author != nil {
+		e.SetAuthor(s.Author.apply(e.NewAuthor()))
+	}
+	if s.Footer != nil {
+		e.SetFooter(s.Footer.apply(e.NewFooter()))
+	}
+	if s.Image != nil {
+		e.SetImage(s.Image.applyImage(e.NewImage()))
+	}
+	if s.Thumbnail != nil {
+		e.SetThumbnail(s.Thumbnail.applyThumbnail(e.NewThumbnail()))
+	}
+	if s.Video != nil {
+		e.SetVideo(s.Video.apply(e.NewVideo()))
+	}
+	if s.Provider != nil {
+		e.SetProvider(s.Provider.apply(e.NewProvider()))
+	}
+	return e
+}
+
+// apply sets every property in the spec on f through its setters, then returns the pointer to the FieldBuilder
+func (s *fieldSpec) apply(f *FieldBuilder) *FieldBuilder {
+	return f.SetName(s.Name).SetValue(s.Value).SetInline(s.Inline)
+}
+
+// apply sets every property in the spec on a through its setters, then returns the pointer to the AuthorBuilder
+func (s *authorSpec) apply(a *AuthorBuilder) *AuthorBuilder {
+	a.SetName(s.Name)
+	if s.URL != `` {
+		a.SetURL(s.URL)
+	}
+	if s.IconURL != `` {
+		a.SetIconURL(s.IconURL)
+	}
+	if s.ProxyIconURL != `` {
+		a.SetProxyIconURL(s.ProxyIconURL)
+	}
+	return a
+}
+
+// apply sets every property in the spec on f through its setters, then returns the pointer to the FooterBuilder
+func (s *footerSpec) apply(f *FooterBuilder) *FooterBuilder {
+	f.SetText(s.Text)
+	if s.IconURL != `` {
+		f.SetIconURL(s.IconURL)
+	}
+	if s.ProxyIconURL != `` {
+		f.SetProxyIconURL(s.ProxyIconURL)
+	}
+	return f
+}
+
+// applyImage sets every property in the spec on i through its setters, then returns the pointer to the ImageBuilder
+func (s *imageSpec) applyImage(i *ImageBuilder) *ImageBuilder {
+	if s.URL != `` {
+		i.SetURL(s.URL)
+	}
+	if s.ProxyURL != `` {
+		i.SetProxyURL(s.ProxyURL)
+	}
+	if s.Height != 0 {
+		i.SetHeight(s.Height)
+	}
+	if s.Width != 0 {
+		i.SetWidth(s.Width)
+	}
+	return i
+}
+
+/*
+applyThumbnail sets every property in the spec on t through its setters, then returns the pointer to the
+ThumbnailBuilder
+*/
+func (s *imageSpec) applyThumbnail(t *ThumbnailBuilder) *ThumbnailBuilder {
+	if s.URL != `` {
+		t.SetURL(s.URL)
+	}
+	if s.ProxyURL != `` {
+		t.SetProxyURL(s.ProxyURL)
+	}
+	if s.Height != 0 {
+		t.SetHeight(s.Height)
+	}
+	if s.Width != 0 {
+		t.SetWidth(s.Width)
+	}
+	return t
+}
+
+// apply sets every property in the spec on v through its setters, then returns the pointer to the VideoBuilder
+func (s *videoSpec) apply(v *VideoBuilder) *VideoBuilder {
+	if s.URL != `` {
+		v.SetURL(s.URL)
+	}
+	if s.Height != 0 {
+		v.SetHeight(s.Height)
+	}
+	if s.Width != 0 {
+		v.SetWidth(s.Width)
+	}
+	return v
+}
+
+// apply sets every property in the spec on p through its setters, then returns the pointer to the ProviderBuilder
+func (s *providerSpec) apply(p *ProviderBuilder) *ProviderBuilder {
+	p.SetName(s.Name)
+	if s.URL != `` {
+		p.SetURL(s.URL)
+	}
+	return p
+}
@@ -0,0 +1,271 @@
+package disgobed
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Nightmarlin/disgobed/md"
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+)
+
+/*
+EmbedTemplate is an embed whose text and url properties are text/template templates, for sending embeds of the same
+shape with different data. Never create it directly, instead use the NewEmbedTemplate function
+
+	joined, err := NewEmbedTemplate(&disgord.Embed{
+		Title:     `User {{.Name}} joined`,
+		Thumbnail: &disgord.EmbedThumbnail{URL: `{{.AvatarURL}}`},
+	})
+	...
+	embed, err := joined.Execute(member)
+
+The templates are parsed once when the EmbedTemplate is created. Execute may then be called from many goroutines at
+once, as long as the settings are not changed while it runs.
+
+Values placed in the title, description and fields are escaped for discord markdown, so untrusted text cannot change
+the formatting or mention anyone. The escaping depends on where the value is placed, in the spirit of html/template, so
+values inside code blocks and inline code are escaped for those instead. Use the raw function to place a value that is
+already markdown, or pass it as an md.Raw
+
+	Description: `{{.Name}} said {{raw .Quote}} in ` + "`{{.Channel}}`",
+
+Text that does not render markdown, such as urls and the footer, is placed as it is
+*/
+type EmbedTemplate struct {
+	source *disgord.Embed
+	opts   builderOptions
+
+	// templates holds the parsed template of every text and url property that has one, keyed by its path
+	templates map[string]*template.Template
+	// paths holds the keys of templates in sorted order, so Execute reports the same error for the same data every time
+	paths []string
+}
+
+/*
+NewEmbedTemplate parses every text and url property of source as a template and returns an EmbedTemplate that fills
+them in. Properties without template actions are used as they are, as are the colour, timestamp, sizes and inline
+settings. Templates use the missingkey=error option, so referencing data that does not exist fails rather than
+rendering "<no value>". The returned error describes a property that is not a valid template, and source is
+copied, so it can be changed afterwards without changing the EmbedTemplate.
+
+An error is also returned for a title, description or field whose markdown context is ambiguous, such as an if whose
+branches end in different contexts or a range whose body opens inline code without closing it, as values after it
+could not be escaped safely
+*/
+func NewEmbedTemplate(source *disgord.Embed) (*EmbedTemplate, error) {
+	res := &EmbedTemplate{source: copyEmbed(source), templates: map[string]*template.Template{}}
+	if res.source == nil {
+		res.source = &disgord.Embed{}
+	}
+	texts := specFromEmbed(res.source).texts()
+	paths := make([]string, 0, len(texts))
+	for path := range texts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths) // Report the same error for the same source every time
+
+	for _, path := range paths {
+		text := *texts[path]
+		if !strings.Contains(text, `{{`) {
+			continue
+		}
+		tmpl, err := template.New(path).Option(`missingkey=error`).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		if rendersMarkdown(path) {
+			if err := escapeMarkdown(tmpl); err != nil {
+				return nil, err
+			}
+		}
+		res.templates[path] = tmpl
+		res.paths = append(res.paths, path)
+	}
+	return res, nil
+}
+
+/*
+SetLimits makes the embeds made by Execute check values against the given limits profile instead of
+validation.DefaultLimits, then returns the pointer to the EmbedTemplate
+*/
+func (t *EmbedTemplate) SetLimits(limits validation.Limits) *EmbedTemplate {
+	t.opts.profile = &limits
+	return t
+}
+
+/*
+EnableTruncation makes the embeds made by Execute cut rendered values that are too long down to their limit, appending
+ellipsis to mark the cut, instead of dropping them. Pass validation.DefaultEllipsis for the standard marker. It then
+returns the pointer to the EmbedTemplate
+*/
+func (t *EmbedTemplate) EnableTruncation(ellipsis string) *EmbedTemplate {
+	t.opts.truncate = true
+	t.opts.ellipsis = ellipsis
+	return t
+}
+
+/*
+DisableTruncation restores the default behaviour of dropping rendered values that are too long, then returns the
+pointer to the EmbedTemplate
+*/
+func (t *EmbedTemplate) DisableTruncation() *EmbedTemplate {
+	t.opts.truncate = false
+	return t
+}
+
+/*
+Execute fills in the templates with data and returns a new EmbedBuilder holding the result. The rendered values are set
+through the builder's setters, so limits are checked against the rendered text rather than the template, and any
+problems are recorded in the builder's errors with the path of the property, such as `fields[2].value`. The returned
+error is only set if a template could not be executed
+*/
+func (t *EmbedTemplate) Execute(data interface{}) (*EmbedBuilder, error) {
+	spec := specFromEmbed(t.source)
+	texts := spec.texts()
+	for _, path := range t.paths {
+		var out strings.Builder
+		if err := t.templates[path].Execute(&out, data); err != nil {
+			return nil, err
+		}
+		*texts[path] = out.String()
+	}
+
+	res := NewEmbed()
+	res.opts = t.opts
+	return spec.apply(res), nil
+}
+
+// escaperNames holds the name of the template function that escapes values for each markdown context
+var escaperNames = map[md.Context]string{
+	md.TextContext:       `_escapeMarkdown`,
+	md.CodeBlockContext:  `_escapeCodeBlock`,
+	md.InlineCodeContext: `_escapeInlineCode`,
+}
+
+// templateFuncs holds the functions available to every EmbedTemplate
+var templateFuncs = template.FuncMap{
+	`raw`: func(value interface{}) md.Raw {
+		return md.Raw(fmt.Sprint(value))
+	},
+	escaperNames[md.TextContext]:       escaper(md.TextContext),
+	escaperNames[md.CodeBlockContext]:  escaper(md.CodeBlockContext),
+	escaperNames[md.InlineCodeContext]: escaper(md.InlineCodeContext),
+}
+
+/*
+escaper returns a template function that escapes values for ctx, leaving md.Raw values as they are. Other md.Node
+values are already safe markdown, so they are only escaped inside code
+*/
+func escaper(ctx md.Context) func(value interface{}) string {
+	return func(value interface{}) string {
+		if raw, ok := value.(md.Raw); ok {
+			return string(raw)
+		}
+		if node, ok := value.(md.Node); ok && ctx == md.TextContext {
+			return node.String()
+		}
+		return ctx.Escape(fmt.Sprint(value))
+	}
+}
+
+// rendersMarkdown checks whether discord renders markdown in the property at path
+func rendersMarkdown(path string) bool {
+	return path == `title` || path == `description` || strings.HasPrefix(path, `fields[`)
+}
+
+/*
+escapeMarkdown adds an escaper to the end of every action in tmpl that prints a value, chosen for the markdown context
+the action is placed in. As in html/template, a branch must end in the same context whichever way it goes, and a range
+body must end in the context it started in, so the context of every action is known before the template runs. The
+returned error describes the first branch that does not
+*/
+func escapeMarkdown(tmpl *template.Template) error {
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if _, err := escapeNode(t.Tree, t.Tree.Root, md.TextContext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contextNames describes each markdown context in errors
+var contextNames = map[md.Context]string{
+	md.TextContext:       `text`,
+	md.CodeBlockContext:  `a code block`,
+	md.InlineCodeContext: `inline code`,
+}
+
+/*
+escapeNode adds escapers to the actions in node, which starts in the context ctx and belongs to tree, and returns the
+context found at its end
+*/
+func escapeNode(tree *parse.Tree, node parse.Node, ctx md.Context) (md.Context, error) {
+	switch n := node.(type) {
+	case *parse.TextNode:
+		return ctx.After(string(n.Text)), nil
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) == 0 { // Declarations and assignments print nothing
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      n.Pos,
+				Args:     []parse.Node{parse.NewIdentifier(escaperNames[ctx]).SetPos(n.Pos)},
+			})
+		}
+	case *parse.ListNode:
+		for _, child := range n.Nodes {
+			var err error
+			if ctx, err = escapeNode(tree, child, ctx); err != nil {
+				return ctx, err
+			}
+		}
+	case *parse.IfNode:
+		return escapeBranch(tree, n, &n.BranchNode, `if`, ctx)
+	case *parse.RangeNode:
+		return escapeBranch(tree, n, &n.BranchNode, `range`, ctx)
+	case *parse.WithNode:
+		return escapeBranch(tree, n, &n.BranchNode, `with`, ctx)
+	}
+	return ctx, nil
+}
+
+/*
+escapeBranch adds escapers to both sides of branch, the body of the named action node, which starts in the context ctx.
+It returns the context found at the end of the branch, or an error if the sides end in different contexts. A range body
+may run any number of times, so it must also end in the context it started in
+*/
+func escapeBranch(
+	tree *parse.Tree, node parse.Node, branch *parse.BranchNode, name string, ctx md.Context,
+) (md.Context, error) {
+	main := ctx
+	if branch.List != nil {
+		var err error
+		if main, err = escapeNode(tree, branch.List, ctx); err != nil {
+			return ctx, err
+		}
+	}
+	location, _ := tree.ErrorContext(node)
+	if name == `range` && main != ctx {
+		return ctx, fmt.Errorf(`template: %s: {{range}} body ends in %s but starts in %s`,
+			location, contextNames[main], contextNames[ctx])
+	}
+
+	other := ctx
+	if branch.ElseList != nil {
+		var err error
+		if other, err = escapeNode(tree, branch.ElseList, ctx); err != nil {
+			return ctx, err
+		}
+	}
+	if main != other {
+		return ctx, fmt.Errorf(`template: %s: {{%s}} branches end in different contexts, %s and %s`,
+			location, name, contextNames[main], contextNames[other])
+	}
+	return main, nil
+}
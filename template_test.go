@@ -0,0 +1,172 @@
+package disgobed
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Nightmarlin/disgobed/md"
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+// templateMember is the data used by the template tests
+type templateMember struct {
+	Name      string
+	AvatarURL string
+	Roles     []string
+}
+
+/*
+TestEmbedTemplate tests embeds can be rendered from templates
+*/
+func TestEmbedTemplate(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test text and url properties are rendered`)
+	joined, err := NewEmbedTemplate(&disgord.Embed{
+		Title:     `User {{.Name}} joined`,
+		Color:     0x00ff00,
+		Thumbnail: &disgord.EmbedThumbnail{URL: `{{.AvatarURL}}`, Height: 64, Width: 64},
+		Footer:    &disgord.EmbedFooter{Text: `Welcome!`},
+		Fields:    []*disgord.EmbedField{{Name: `Roles`, Value: `{{range .Roles}}{{.}} {{end}}`, Inline: true}},
+	})
+	t.CmpNoError(err)
+	embed, err := joined.Execute(templateMember{
+		Name:      `nightmarlin`,
+		AvatarURL: `https://example.com/avatar.png`,
+		Roles:     []string{`admin`, `member`},
+	})
+	t.CmpNoError(err)
+	gotEmbed, gotErrors := embed.Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed, &disgord.Embed{
+		Title:     `User nightmarlin joined`,
+		Color:     0x00ff00,
+		Thumbnail: &disgord.EmbedThumbnail{URL: `https://example.com/avatar.png`, Height: 64, Width: 64},
+		Footer:    &disgord.EmbedFooter{Text: `Welcome!`},
+		Fields:    []*disgord.EmbedField{{Name: `Roles`, Value: `admin member `, Inline: true}},
+	})
+
+	t.Log(`2. test limits are checked on the rendered output`)
+	source := &disgord.Embed{Title: `{{if false}}` + strings.Repeat(`a`, validation.LowerCharLimit) + `{{end}}{{.Name}}`}
+	long, err := NewEmbedTemplate(source)
+	t.CmpNoError(err)
+	source.Title = `changed`
+	embed, err = long.Execute(templateMember{Name: `short`})
+	t.CmpNoError(err)
+	t.Cmp(embed.Title, `short`)
+	t.Cmp(embed.Errors, td.Nil())
+
+	embed, err = long.Execute(templateMember{Name: strings.Repeat(`b`, validation.LowerCharLimit+1)})
+	t.CmpNoError(err)
+	t.Cmp(embed.Title, ``)
+	t.Cmp(embed.Errors, &[]error{
+		validation.NewError(`title`, validation.ErrTooLong, validation.LowerCharLimit, validation.LowerCharLimit+1),
+	})
+
+	embed, err = joined.Execute(templateMember{Name: `x`, AvatarURL: `not a url`})
+	t.CmpNoError(err)
+	t.Cmp(embed.Errors, &[]error{
+		validation.NewError(`fields[0].value`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`thumbnail.url`, validation.ErrInvalidURL, nil, `not a url`),
+	})
+
+	t.Log(`3. test truncation applies to the rendered output`)
+	embed, err = long.EnableTruncation(validation.DefaultEllipsis).
+		Execute(templateMember{Name: strings.Repeat(`c`, validation.LowerCharLimit+10)})
+	t.CmpNoError(err)
+	t.Cmp(validation.CharCount(embed.Title), validation.LowerCharLimit)
+	long.DisableTruncation()
+
+	t.Log(`4. test bad templates and data are reported`)
+	_, err = NewEmbedTemplate(&disgord.Embed{Fields: []*disgord.EmbedField{{Name: `n`, Value: `{{.Name`}}})
+	t.CmpError(err)
+	t.Cmp(err.Error(), td.Contains(`fields[0].value`))
+
+	for i := 0; i < 10; i++ { // The first missing key in path order is reported every time
+		_, err = joined.Execute(map[string]string{`Name`: `no avatar or roles`})
+		t.CmpError(err)
+		t.Cmp(err.Error(), td.Contains(`Roles`))
+	}
+	_, err = joined.Execute(map[string]interface{}{`Name`: `no avatar`, `Roles`: []string{}})
+	t.CmpError(err)
+	t.Cmp(err.Error(), td.Contains(`AvatarURL`))
+
+	t.Log(`5. test templates can be executed from many goroutines`)
+	var wg sync.WaitGroup
+	titles := make([]string, 20)
+	for i := range titles {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			embed, _ := joined.Execute(templateMember{Name: strings.Repeat(`n`, i+1), AvatarURL: `https://example.com`})
+			titles[i] = embed.Title
+		}(i)
+	}
+	wg.Wait()
+	for i, title := range titles {
+		t.Cmp(title, `User `+strings.Repeat(`n`, i+1)+` joined`)
+	}
+
+	t.Log(`6. test values are escaped for the markdown context they are placed in`)
+	quoted, err := NewEmbedTemplate(&disgord.Embed{
+		Title:       `{{.Name}} joined`,
+		Description: "{{.Name}} said {{raw .Quote}}\n```\n{{.Code}}\n```\nin `{{.Channel}}`",
+		URL:         `https://example.com/{{.Path}}`,
+		Footer:      &disgord.EmbedFooter{Text: `{{.Name}}`},
+		Fields:      []*disgord.EmbedField{{Name: `{{if .Name}}{{.Name}}{{end}}`, Value: `{{$n := .Name}}{{$n}}`}},
+	})
+	t.CmpNoError(err)
+	embed, err = quoted.Execute(map[string]interface{}{
+		`Name`:    `*evil* @everyone`,
+		`Quote`:   `**hi**`,
+		`Code`:    "``` @here",
+		`Channel`: "a`b",
+		`Path`:    `a_b`,
+	})
+	t.CmpNoError(err)
+	t.Cmp(embed.Title, "\\*evil\\* @\u200beveryone joined")
+	t.Cmp(embed.Description, "\\*evil\\* @\u200beveryone said **hi**\n```\n`\u200b`\u200b` @here\n```\nin `aˋb`")
+	t.Cmp(embed.URL, `https://example.com/a_b`)
+	t.Cmp(embed.Footer.Text, `*evil* @everyone`)
+	t.Cmp(embed.Fields, []*disgord.EmbedField{
+		{Name: "\\*evil\\* @\u200beveryone", Value: "\\*evil\\* @\u200beveryone"},
+	})
+
+	embed, err = quoted.Execute(map[string]interface{}{
+		`Name`: `name`, `Quote`: md.Raw(`_q_`), `Code`: ``, `Channel`: ``, `Path`: ``,
+	})
+	t.CmpNoError(err)
+	t.Cmp(embed.Description, "name said _q_\n```\n\n```\nin ``")
+
+	embed, err = quoted.Execute(map[string]interface{}{
+		`Name`: md.Bold(md.Text(`_b_`)), `Quote`: ``, `Code`: md.Bold(md.Text(`c`)), `Channel`: ``, `Path`: ``,
+	})
+	t.CmpNoError(err)
+	t.Cmp(embed.Title, `**\_b\_** joined`)
+	t.Cmp(embed.Description, "**\\_b\\_** said \n```\n**c**\n```\nin ``")
+
+	t.Log(`7. test branches that could leave values unescaped are rejected`)
+	for _, desc := range []string{
+		"{{range .}}`{{.}}{{end}}",
+		"{{if .}}`{{end}}{{.}}",
+		"{{with .}}```{{else}}x{{end}}",
+		"{{range .}}a{{else}}`{{end}}",
+	} {
+		_, err = NewEmbedTemplate(&disgord.Embed{Description: desc})
+		t.CmpError(err, desc)
+		t.Cmp(err.Error(), td.Re(`\{\{(range|if|with)\}\}`), desc)
+	}
+	_, err = NewEmbedTemplate(&disgord.Embed{Footer: &disgord.EmbedFooter{Text: "{{range .}}`{{.}}{{end}}"}})
+	t.CmpNoError(err) // The footer renders no markdown
+
+	listed, err := NewEmbedTemplate(&disgord.Embed{
+		Description: "{{range .}}`{{.}}` {{end}}{{if .}}`{{index . 0}}{{else}}`{{end}}`",
+	})
+	t.CmpNoError(err)
+	embed, err = listed.Execute([]string{`a*`, "b`"})
+	t.CmpNoError(err)
+	t.Cmp(embed.Description, "`a*` `bˋ` `a*`")
+}
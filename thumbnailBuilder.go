@@ -1,7 +1,8 @@
 package disgobed
 
 import (
-	"fmt"
+	"io"
+	"io/ioutil"
 
 	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
@@ -13,17 +14,47 @@ ThumbnailBuilder wraps the disgord.EmbedThumbnail type and adds features
 type ThumbnailBuilder struct {
 	*disgord.EmbedThumbnail
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before an thumbnail is
 attached. Finalize will also purge the error cache!
+Warnings are dropped, so a nil result means the thumbnail has no problems discord will reject
 */
 func (t *ThumbnailBuilder) Finalize() (*disgord.EmbedThumbnail, *[]error) {
 	defer func(t *ThumbnailBuilder) { t.Errors = nil }(t)
+	return t.EmbedThumbnail, validation.FilterSeverity(t.Errors, t.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a ThumbnailBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (t *ThumbnailBuilder) peek() (*disgord.EmbedThumbnail, *[]error) {
 	return t.EmbedThumbnail, t.Errors
 }
 
+/*
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the ThumbnailBuilder
+*/
+func (t *ThumbnailBuilder) Strict() *ThumbnailBuilder {
+	t.opts.strict = true
+	return t
+}
+
+/*
+SetLimits makes the ThumbnailBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the ThumbnailBuilder
+*/
+func (t *ThumbnailBuilder) SetLimits(limits validation.Limits) *ThumbnailBuilder {
+	t.opts.profile = &limits
+	return t
+}
+
 /*
 NewThumbnail creates and returns a pointer to a new empty thumbnail
 */
@@ -35,42 +66,121 @@ func NewThumbnail() *ThumbnailBuilder {
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in AuthorBuilder. If the pointer is nil a new error slice
-is created. This function takes the same inputs as fmt.Sprintf
+NewThumbnailFromBytes creates a thumbnail that shows the image in data. The image is registered with attachments under the
+given file name and the thumbnail's url and dimensions are set from it. See ThumbnailBuilder.SetImageData
 */
-func (t *ThumbnailBuilder) addError(format string, values ...interface{}) {
+func NewThumbnailFromBytes(attachments *Attachments, name string, data []byte) *ThumbnailBuilder {
+	return NewThumbnail().SetImageData(attachments, name, data)
+}
+
+/*
+NewThumbnailFromReader works like NewThumbnailFromBytes, but reads the image from r
+*/
+func NewThumbnailFromReader(attachments *Attachments, name string, r io.Reader) *ThumbnailBuilder {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		res := NewThumbnail()
+		res.addRawError(err)
+		return res
+	}
+	return NewThumbnailFromBytes(attachments, name, data)
+}
+
+/*
+SetImageData registers the image in data with attachments under the given file name, then sets the thumbnail's url to the
+attachment and its height and width to those of the image. It then returns the pointer to the ThumbnailBuilder. The image
+must be a png, jpeg, gif or webp, otherwise a validation.ErrUnsupportedImage or validation.ErrCorruptImage error is
+recorded and nothing is registered. If name has no extension, that of the image format is added
+(This function fails silently)
+*/
+func (t *ThumbnailBuilder) SetImageData(attachments *Attachments, name string, data []byte) *ThumbnailBuilder {
+	if attachments == nil {
+		t.addError(`url`, validation.ErrMissingAttachment, nil, name)
+		return t
+	}
+	attachment, info, err := attachments.addImage(`url`, name, data)
+	if err != nil {
+		t.addRawError(err)
+		return t
+	}
+	return t.SetAttachment(attachment).SetHW(info.Height, info.Width)
+}
+
+/*
+addError records a validation error for the property at path in the error slice stored in ThumbnailBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
+*/
+func (t *ThumbnailBuilder) addError(path string, code error, limit interface{}, value interface{}) {
+	if t.Errors == nil {
+		t.Errors = &[]error{}
+	}
+	err := validation.NewError(path, code, limit, value)
+	t.opts.check(err)
+	*t.Errors = append(*t.Errors, err)
+}
+
+/*
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
+*/
+func (t *ThumbnailBuilder) addRawError(err error) {
+	t.opts.check(err)
 	if t.Errors == nil {
 		t.Errors = &[]error{}
 	}
-	*t.Errors = append(*t.Errors, fmt.Errorf(format, values...))
+	*t.Errors = append(*t.Errors, err)
 }
 
 /*
 SetURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the ThumbnailBuilder (if
-the string does not start with one of these, no URL will be added). It then returns the pointer to the ThumbnailBuilder
+the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the ThumbnailBuilder
 structure
+http:// urls are accepted but recorded as a warning
 (This function fails silently)
 */
 func (t *ThumbnailBuilder) SetURL(url string) *ThumbnailBuilder {
-	if validation.CheckValidIconURL(url) {
+	if err := validation.ValidateURL(`url`, url, true, t.opts.limits()); err == nil {
 		t.URL = url
+		if warning := validation.LintURL(`url`, url); warning != nil {
+			t.addRawError(warning)
+		}
 	} else {
-		t.addError(validation.InvalidUrlErrTemplateString, `thumbnail url`, url)
+		t.addRawError(err)
 	}
 	return t
 }
 
+/*
+SetAttachment sets the thumbnail url to the `attachment://` url of the given attachment, then returns the pointer to the
+ThumbnailBuilder. The attachment must be uploaded with the message, such as by passing its registry to
+MessageBuilder.SetAttachments. A nil attachment is not added
+(This function fails silently)
+*/
+func (t *ThumbnailBuilder) SetAttachment(attachment *Attachment) *ThumbnailBuilder {
+	if attachment == nil {
+		t.addError(`url`, validation.ErrMissingAttachment, nil, validation.AttachmentURLPrefix)
+		return t
+	}
+	return t.SetURL(attachment.URL())
+}
+
 /*
 SetProxyURL takes an image address string prefixed with https:// / http:// / attachment:// and adds it to the ThumbnailBuilder
-(if the string does not start with one of these, no URL will be added). It then returns the pointer to the ThumbnailBuilder
+(if the string is not a valid url of one of these kinds, no URL will be added). It then returns the pointer to the ThumbnailBuilder
 structure
+Discord ignores proxy urls on input, so setting one is recorded as a warning. http:// urls are also recorded as
+warnings
 (This function fails silently)
 */
 func (t *ThumbnailBuilder) SetProxyURL(proxyUrl string) *ThumbnailBuilder {
-	if validation.CheckValidIconURL(proxyUrl) {
-		t.URL = proxyUrl
+	if err := validation.ValidateURL(`proxy_url`, proxyUrl, true, t.opts.limits()); err == nil {
+		t.ProxyURL = proxyUrl
+		if warning := validation.LintURL(`proxy_url`, proxyUrl); warning != nil {
+			t.addRawError(warning)
+		}
+		t.addRawError(validation.NewWarning(`proxy_url`, validation.ErrIgnoredProperty, nil, proxyUrl))
 	} else {
-		t.addError(validation.InvalidUrlErrTemplateString, `thumbnail proxyUrl`, proxyUrl)
+		t.addRawError(err)
 	}
 	return t
 }
@@ -85,7 +195,12 @@ func (t *ThumbnailBuilder) SetHW(h int, w int) *ThumbnailBuilder {
 		t.Height = h
 		t.Width = w
 	} else {
-		t.addError(validation.InvalidHWErrTemplateString, `thumbnail`, h, `thumbnail`, w)
+		if h <= 0 {
+			t.addError(`height`, validation.ErrNotPositive, 0, h)
+		}
+		if w <= 0 {
+			t.addError(`width`, validation.ErrNotPositive, 0, w)
+		}
 	}
 	return t
 }
@@ -99,7 +214,7 @@ func (t *ThumbnailBuilder) SetHeight(h int) *ThumbnailBuilder {
 	if h > 0 {
 		t.Height = h
 	} else {
-		t.addError(validation.ValueNotBetweenErrTemplateString, `thumbnail height`, h, 0, `infinity`)
+		t.addError(`height`, validation.ErrNotPositive, 0, h)
 	}
 	return t
 }
@@ -113,7 +228,32 @@ func (t *ThumbnailBuilder) SetWidth(w int) *ThumbnailBuilder {
 	if w > 0 {
 		t.Width = w
 	} else {
-		t.addError(validation.ValueNotBetweenErrTemplateString, `thumbnail width`, w, 0, `infinity`)
+		t.addError(`width`, validation.ErrNotPositive, 0, w)
 	}
 	return t
 }
+
+/*
+ClearURL removes the thumbnail's url, then returns the pointer to the ThumbnailBuilder
+*/
+func (t *ThumbnailBuilder) ClearURL() *ThumbnailBuilder {
+	t.URL = ``
+	return t
+}
+
+/*
+ClearProxyURL removes the thumbnail's proxy url, then returns the pointer to the ThumbnailBuilder
+*/
+func (t *ThumbnailBuilder) ClearProxyURL() *ThumbnailBuilder {
+	t.ProxyURL = ``
+	return t
+}
+
+/*
+ClearHW removes the thumbnail's height and width, then returns the pointer to the ThumbnailBuilder
+*/
+func (t *ThumbnailBuilder) ClearHW() *ThumbnailBuilder {
+	t.Height = 0
+	t.Width = 0
+	return t
+}
@@ -0,0 +1,53 @@
+package disgobed
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/BurntSushi/toml"
+)
+
+/*
+FromTOML reads an embed from a TOML document and returns an EmbedBuilder holding it. The document uses the same
+property names as the discord API, with the author, footer and other embedded objects as tables and fields as an array
+of tables
+
+	title = "Server update"
+	color = "#ff8800"
+	timestamp = 2020-05-01T22:00:00Z
+	description = """
+	We are moving to a new host tonight.
+	Expect a few minutes of downtime."""
+
+	[[fields]]
+	name = "When"
+	value = "22:00 UTC"
+
+Colours can be written as integers or hex strings, and timestamps as date-times, RFC 3339 strings or "now". Every
+property is set through the builder's setters, so invalid values are left out and recorded in the builder's errors with
+the path they were read from, such as `fields[2].value`. The returned error is only set if data is not valid TOML for
+an embed, which includes TOML with keys an embed does not have, such as a misspelt `colour`
+*/
+func FromTOML(data []byte) (*EmbedBuilder, error) {
+	var spec embedSpec
+	meta, err := toml.Decode(string(data), &spec)
+	if err != nil {
+		return nil, err
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf(`toml: unknown key %q`, undecoded[0].String())
+	}
+	return spec.apply(NewEmbed()), nil
+}
+
+/*
+ReadTOML works like FromTOML, but reads the embed from r
+*/
+func ReadTOML(r io.Reader) (*EmbedBuilder, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromTOML(data)
+}
@@ -0,0 +1,100 @@
+package disgobed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestTOML tests embeds can be read from TOML documents
+*/
+func TestTOML(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test embeds are read through the setters`)
+	embed, err := FromTOML([]byte(`
+title = "Server update"
+color = "0xFF8800"
+timestamp = 2020-05-01T12:30:00Z
+description = """
+We are moving to a new host tonight.
+Expect a few minutes of downtime."""
+
+[author]
+name = "bot"
+url = "https://example.com"
+
+[image]
+url = "https://example.com/image.png"
+height = 10
+width = 20
+
+[[fields]]
+name = "When"
+value = "22:00 UTC"
+
+[[fields]]
+name = "Where"
+value = "Everywhere"
+inline = true
+`))
+	t.CmpNoError(err)
+	gotEmbed, gotErrors := embed.Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed, &disgord.Embed{
+		Title:       `Server update`,
+		Description: "We are moving to a new host tonight.\nExpect a few minutes of downtime.",
+		Timestamp:   disgord.Time{Time: time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)},
+		Color:       0xff8800,
+		Author:      &disgord.EmbedAuthor{Name: `bot`, URL: `https://example.com`},
+		Image:       &disgord.EmbedImage{URL: `https://example.com/image.png`, Height: 10, Width: 20},
+		Fields: []*disgord.EmbedField{
+			{Name: `When`, Value: `22:00 UTC`},
+			{Name: `Where`, Value: `Everywhere`, Inline: true},
+		},
+	})
+
+	t.Log(`2. test string timestamps and "now" are accepted`)
+	embed, err = ReadTOML(strings.NewReader(`timestamp = "2021-01-02T03:04:05Z"`))
+	t.CmpNoError(err)
+	t.Cmp(embed.Timestamp.Time, time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	before := time.Now()
+	embed, err = FromTOML([]byte(`timestamp = "now"`))
+	t.CmpNoError(err)
+	t.Cmp(embed.Timestamp.Time, td.Between(before, time.Now()))
+
+	t.Log(`3. test invalid values are reported with their paths`)
+	embed, err = FromTOML([]byte(`
+color = "#1000000"
+
+[footer]
+text = "` + strings.Repeat(`a`, validation.UpperCharLimit+1) + `"
+`))
+	t.CmpNoError(err)
+	_, gotErrors = embed.Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`color`, validation.ErrOutOfRange, validation.MaxColorValue, 0x1000000),
+		validation.NewError(`footer.text`, validation.ErrTooLong, validation.UpperCharLimit, validation.UpperCharLimit+1),
+	})
+
+	embed, err = FromTOML([]byte(`color = ""`))
+	t.CmpNoError(err)
+	_, gotErrors = embed.Finalize()
+	t.Cmp(gotErrors, &[]error{validation.NewError(`color`, validation.ErrInvalidColor, nil, ``)})
+
+	t.Log(`4. test malformed toml is rejected`)
+	_, err = FromTOML([]byte(`color = true`))
+	t.CmpError(err)
+	_, err = FromTOML([]byte("title = \"t\"\ncolour = \"#ff8800\""))
+	t.CmpError(err)
+	t.Cmp(err.Error(), td.Contains(`colour`))
+	_, err = FromTOML([]byte("[footer]\ntext = \"t\"\nicon = \"https://example.com/a.png\""))
+	t.CmpError(err)
+	t.Cmp(err.Error(), td.Contains(`footer.icon`))
+}
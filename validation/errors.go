@@ -0,0 +1,352 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Severity describes how likely it is that discord will reject an embed because of a validation problem. The zero value
+is SeverityError so that an Error created without a severity is always treated as fatal
+*/
+type Severity int
+
+const (
+	// SeverityError marks a problem that will cause discord to reject the embed
+	SeverityError Severity = iota
+
+	// SeverityWarning marks a problem that discord will accept, but that is probably a mistake
+	SeverityWarning
+
+	// SeverityInfo marks a purely informational diagnostic
+	SeverityInfo
+)
+
+/*
+AtLeast checks whether s is at least as severe as threshold, such that SeverityError is at least SeverityWarning but
+SeverityInfo is not
+*/
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s <= threshold
+}
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return `error`
+	case SeverityWarning:
+		return `warning`
+	case SeverityInfo:
+		return `info`
+	default:
+		return fmt.Sprintf(`severity(%d)`, int(s))
+	}
+}
+
+var (
+	// ErrTooLong is the rule broken when a property has more characters than its limit allows
+	ErrTooLong = errors.New(`value exceeds character limit`)
+
+	// ErrTotalTooLong is the rule broken when the combined character count of an embed exceeds MaxTotalCharLimit
+	ErrTotalTooLong = errors.New(`total character count exceeds limit`)
+
+	// ErrCombinedTooLong is the rule broken when the combined character count of all of a message's embeds exceeds
+	// MaxTotalCharLimit
+	ErrCombinedTooLong = errors.New(`combined character count exceeds limit`)
+
+	// ErrTooMany is the rule broken when a list property has more items than its limit allows
+	ErrTooMany = errors.New(`item count exceeds limit`)
+
+	// ErrOverflow is the rule broken when a value is too long to fit in the space left for it. The Value of the error
+	// is the number of characters that did not fit
+	ErrOverflow = errors.New(`value does not fit`)
+
+	// ErrEmpty is the rule broken when a property must not be empty
+	ErrEmpty = errors.New(`value is empty`)
+
+	// ErrInvalidURL is the rule broken when a url property is not acceptable to discord
+	ErrInvalidURL = errors.New(`invalid url`)
+
+	// ErrOutOfRange is the rule broken when a numeric property is not between 0 and its limit
+	ErrOutOfRange = errors.New(`value out of range`)
+
+	// ErrNotPositive is the rule broken when a numeric property must be greater than 0
+	ErrNotPositive = errors.New(`value is not positive`)
+
+	// ErrInvalidType is the rule broken when the embed type is not one of the known embed types
+	ErrInvalidType = errors.New(`invalid embed type`)
+
+	// ErrInvalidTimestamp is the rule broken when a timestamp cannot be read
+	ErrInvalidTimestamp = errors.New(`invalid timestamp`)
+
+	// ErrInvalidColor is the rule broken when a colour cannot be read
+	ErrInvalidColor = errors.New(`invalid color`)
+
+	// ErrNotFound is the rule broken when an item is looked up by a name that nothing has
+	ErrNotFound = errors.New(`item not found`)
+
+	// ErrMissingAttachment is the rule broken when an `attachment://` url does not reference an attached file
+	ErrMissingAttachment = errors.New(`attachment not found`)
+
+	// ErrDuplicateAttachment is reported as a warning when more than one embed in a message references the same
+	// attachment, as discord only shows an attachment in the first embed that uses it
+	ErrDuplicateAttachment = errors.New(`attachment referenced by more than one embed`)
+
+	// ErrUnusedAttachment is reported as a warning when a file registered for use by embeds is not referenced by any
+	// of them
+	ErrUnusedAttachment = errors.New(`attachment not referenced`)
+
+	// ErrDuplicateFileName is the rule broken when a message has more than one file with the same name, as an
+	// `attachment://` url naming it could not tell them apart
+	ErrDuplicateFileName = errors.New(`file name already used`)
+
+	// ErrUnsupportedImage is the rule broken when image data is not in a format discord renders
+	ErrUnsupportedImage = errors.New(`unsupported image format`)
+
+	// ErrCorruptImage is the rule broken when image data is in a format discord renders but cannot be read
+	ErrCorruptImage = errors.New(`corrupt image`)
+
+	// ErrTruncated is reported as a warning when a value was shortened to fit its limit
+	ErrTruncated = errors.New(`value truncated`)
+
+	// ErrInsecureURL is reported as a warning when a url uses http:// instead of https://
+	ErrInsecureURL = errors.New(`insecure url`)
+
+	// ErrIgnoredProperty is reported as a warning when a property is set that discord ignores on input, such as a
+	// proxy url
+	ErrIgnoredProperty = errors.New(`property is ignored by discord`)
+
+	// ErrIgnoredType is reported as a warning when the embed type is not "rich", as clients ignore the type of embeds
+	// sent by bots
+	ErrIgnoredType = errors.New(`embed type is ignored by clients`)
+)
+
+/*
+Error describes a single validation problem. It can be compared against the sentinel errors in this package using
+errors.Is, and retrieved from an error chain using errors.As
+
+	var verr *validation.Error
+	if errors.As(err, &verr) && errors.Is(verr, validation.ErrTooLong) {
+		fmt.Printf("%v is %v characters too long", verr.Path, verr.Value.(int)-verr.Limit.(int))
+	}
+*/
+type Error struct {
+	// Path is the location of the offending property, such as `title` or `fields[3].value`. An empty path refers to
+	// the embed itself
+	Path string
+
+	// Code is the sentinel error for the rule that was broken, such as ErrTooLong
+	Code error
+
+	// Limit is the limit the rule enforces, if it has one
+	Limit interface{}
+
+	// Value is the offending value, or the measured quantity (such as the length) that broke the limit
+	Value interface{}
+
+	// Severity describes how likely discord is to reject the embed because of this problem
+	Severity Severity
+}
+
+/*
+NewError creates an error-level validation error for the property at path
+*/
+func NewError(path string, code error, limit interface{}, value interface{}) *Error {
+	return &Error{
+		Path:     path,
+		Code:     code,
+		Limit:    limit,
+		Value:    value,
+		Severity: SeverityError,
+	}
+}
+
+/*
+NewWarning creates a warning-level validation error for the property at path
+*/
+func NewWarning(path string, code error, limit interface{}, value interface{}) *Error {
+	res := NewError(path, code, limit, value)
+	res.Severity = SeverityWarning
+	return res
+}
+
+// Error returns a human readable description of the problem
+func (e *Error) Error() string {
+	path := e.Path
+	if path == `` {
+		path = `embed`
+	}
+
+	switch e.Code {
+	case ErrTooLong:
+		return fmt.Sprintf(`%v exceeds %v characters: length = %v`, path, e.Limit, e.Value)
+	case ErrTotalTooLong:
+		if e.Path == `` {
+			return fmt.Sprintf(`embed total character count exceeds %v: length = %v`, e.Limit, e.Value)
+		}
+		return fmt.Sprintf(`%v would make the embed total character count exceed %v: length = %v`, path, e.Limit, e.Value)
+	case ErrCombinedTooLong:
+		return fmt.Sprintf(`%v combined character count exceeds %v: length = %v`, path, e.Limit, e.Value)
+	case ErrTooMany:
+		return fmt.Sprintf(`%v count %v exceeds %v`, path, e.Value, e.Limit)
+	case ErrOverflow:
+		return fmt.Sprintf(`%v: %v characters did not fit`, path, e.Value)
+	case ErrEmpty:
+		return fmt.Sprintf(`%v should not be empty if set`, path)
+	case ErrInvalidURL:
+		return fmt.Sprintf(`%v '%v' is not a valid url`, path, e.Value)
+	case ErrOutOfRange:
+		return fmt.Sprintf(`%v '%v' is not between 0 and %v`, path, e.Value, e.Limit)
+	case ErrNotPositive:
+		return fmt.Sprintf(`%v '%v' is less than or equal to 0`, path, e.Value)
+	case ErrInvalidType:
+		return fmt.Sprintf(`%v '%v' is not one of "rich" | "image" | "video" | "gifv" | "link" | "article"`, path, e.Value)
+	case ErrInvalidTimestamp:
+		return fmt.Sprintf(`%v '%v' is not an RFC 3339 timestamp`, path, e.Value)
+	case ErrInvalidColor:
+		return fmt.Sprintf(`%v '%v' is not a hex color`, path, e.Value)
+	case ErrNotFound:
+		return fmt.Sprintf(`%v has no item called '%v'`, path, e.Value)
+	case ErrMissingAttachment:
+		return fmt.Sprintf(`%v '%v' does not reference an attached file`, path, e.Value)
+	case ErrDuplicateAttachment:
+		return fmt.Sprintf(`%v '%v' is already used by another embed`, path, e.Value)
+	case ErrUnusedAttachment:
+		return fmt.Sprintf(`%v '%v' is not referenced by any embed`, path, e.Value)
+	case ErrUnsupportedImage:
+		return fmt.Sprintf(`%v is not a png, jpeg, gif or webp image`, path)
+	case ErrCorruptImage:
+		return fmt.Sprintf(`%v is not a readable %v image`, path, e.Value)
+	case ErrTruncated:
+		return fmt.Sprintf(`%v was truncated to %v characters: length = %v`, path, e.Limit, e.Value)
+	case ErrInsecureURL:
+		return fmt.Sprintf(`%v '%v' should use https://`, path, e.Value)
+	case ErrIgnoredProperty:
+		return fmt.Sprintf(`%v is ignored by discord and will not be shown`, path)
+	case ErrIgnoredType:
+		return fmt.Sprintf(`%v '%v' is ignored by clients, embeds sent by bots are always "rich"`, path, e.Value)
+	default:
+		return fmt.Sprintf(`%v: %v (value = '%v', limit = %v)`, path, e.Code, e.Value, e.Limit)
+	}
+}
+
+// Unwrap returns the sentinel error for the broken rule, allowing errors.Is(err, ErrTooLong) and similar
+func (e *Error) Unwrap() error {
+	return e.Code
+}
+
+/*
+WithPrefix returns a copy of the error with its path placed beneath prefix, such that a `name` error with the prefix
+`fields[3]` becomes a `fields[3].name` error. The original error is left unchanged
+*/
+func (e *Error) WithPrefix(prefix string) *Error {
+	res := *e
+	switch {
+	case prefix == ``:
+	case e.Path == ``:
+		res.Path = prefix
+	default:
+		res.Path = prefix + `.` + e.Path
+	}
+	return &res
+}
+
+/*
+NewInfo creates an info-level validation error for the property at path
+*/
+func NewInfo(path string, code error, limit interface{}, value interface{}) *Error {
+	res := NewError(path, code, limit, value)
+	res.Severity = SeverityInfo
+	return res
+}
+
+/*
+SeverityOf returns the severity of err. Errors that are not validation errors are always treated as error-level
+*/
+func SeverityOf(err error) Severity {
+	var verr *Error
+	if errors.As(err, &verr) {
+		return verr.Severity
+	}
+	return SeverityError
+}
+
+/*
+HasErrors checks whether errs contains anything at SeverityError. Errors that are not validation errors are always
+treated as error-level
+*/
+func HasErrors(errs *[]error) bool {
+	return HasSeverity(errs, SeverityError)
+}
+
+/*
+HasSeverity checks whether errs contains anything at least as severe as threshold, so HasSeverity(errs, SeverityWarning)
+is true if there are any errors or warnings
+*/
+func HasSeverity(errs *[]error, threshold Severity) bool {
+	if errs == nil {
+		return false
+	}
+	for _, err := range *errs {
+		if SeverityOf(err).AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+FilterSeverity returns the errors in errs that are at least as severe as threshold. If none are, nil is returned
+*/
+func FilterSeverity(errs *[]error, threshold Severity) *[]error {
+	if errs == nil {
+		return nil
+	}
+	var res []error
+	for _, err := range *errs {
+		if SeverityOf(err).AtLeast(threshold) {
+			res = append(res, err)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return &res
+}
+
+/*
+MergeErrors combines the error slices given to it into one, ignoring nil slices. If there are no errors at all, nil is
+returned
+*/
+func MergeErrors(errs ...*[]error) *[]error {
+	var res []error
+	for _, e := range errs {
+		if e != nil {
+			res = append(res, *e...)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return &res
+}
+
+/*
+PrefixErrors places every validation error in errs beneath prefix (see Error.WithPrefix). Errors that are not validation
+errors are returned unchanged. A nil slice results in nil
+*/
+func PrefixErrors(prefix string, errs *[]error) *[]error {
+	if errs == nil {
+		return nil
+	}
+	res := make([]error, 0, len(*errs))
+	for _, err := range *errs {
+		var verr *Error
+		if errors.As(err, &verr) {
+			err = verr.WithPrefix(prefix)
+		}
+		res = append(res, err)
+	}
+	return &res
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestError(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test errors.Is and errors.As work through wrapping`)
+	var err error = fmt.Errorf(`wrapped: %w`, NewError(`title`, ErrTooLong, LowerCharLimit, 300))
+	t.True(errors.Is(err, ErrTooLong))
+	t.False(errors.Is(err, ErrInvalidURL))
+
+	var verr *Error
+	t.True(errors.As(err, &verr))
+	t.Cmp(verr, &Error{
+		Path:     `title`,
+		Code:     ErrTooLong,
+		Limit:    LowerCharLimit,
+		Value:    300,
+		Severity: SeverityError,
+	})
+
+	t.Log(`2. test error messages`)
+	t.Cmp(verr.Error(), `title exceeds 256 characters: length = 300`)
+	t.Cmp(NewError(``, ErrTotalTooLong, MaxTotalCharLimit, 6001).Error(),
+		`embed total character count exceeds 6000: length = 6001`)
+	t.Cmp(NewError(`url`, ErrInvalidURL, nil, `nope`).Error(), `url 'nope' is not a valid url`)
+
+	t.Log(`3. test path prefixing`)
+	t.Cmp(NewError(`name`, ErrEmpty, nil, ``).WithPrefix(`fields[3]`).Path, `fields[3].name`)
+	t.Cmp(NewError(``, ErrTotalTooLong, nil, nil).WithPrefix(`embeds[1]`).Path, `embeds[1]`)
+	t.Cmp(PrefixErrors(`author`, nil), td.Nil())
+
+	plain := errors.New(`plain`)
+	t.Cmp(PrefixErrors(`author`, &[]error{NewError(`name`, ErrTooLong, 256, 300), plain}), &[]error{
+		NewError(`author.name`, ErrTooLong, 256, 300),
+		plain,
+	})
+}
+
+func TestSeverity(tt *testing.T) {
+	t := td.NewT(tt)
+
+	errs := &[]error{
+		NewInfo(`title`, ErrEmpty, nil, ``),
+		NewWarning(`url`, ErrInsecureURL, nil, `http://example.com`),
+	}
+
+	t.Log(`1. test severity ordering`)
+	t.True(SeverityError.AtLeast(SeverityWarning))
+	t.False(SeverityInfo.AtLeast(SeverityWarning))
+	t.Cmp(SeverityOf(errors.New(`plain`)), SeverityError)
+
+	t.Log(`2. test checking for severities`)
+	t.False(HasErrors(errs))
+	t.True(HasSeverity(errs, SeverityWarning))
+	t.True(HasErrors(&[]error{errors.New(`plain`)}))
+
+	t.Log(`3. test filtering by severity`)
+	t.Cmp(FilterSeverity(errs, SeverityError), td.Nil())
+	t.Cmp(FilterSeverity(errs, SeverityWarning), &[]error{(*errs)[1]})
+	t.Cmp(FilterSeverity(errs, SeverityInfo), errs)
+	t.Cmp(FilterSeverity(nil, SeverityInfo), td.Nil())
+}
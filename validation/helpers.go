@@ -1,26 +1,91 @@
 package validation
 
 import (
+	"net/url"
+	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-// CheckValidIconURL checks that discord will accept the given url in the restricted fields
+/*
+CharCount returns the number of characters discord counts in s. Discord measures its limits in unicode code points, not
+bytes or UTF-16 code units, so
+
+	CharCount(`日本語`) == 3 // 9 bytes
+	CharCount(`😀`) == 1 // 4 bytes, 2 UTF-16 code units
+	CharCount(`é`) == 2 // `e` followed by a combining acute accent
+	CharCount(`👩‍👩‍👧`) == 5 // three emoji joined by two zero width joiners
+
+Invalid UTF-8 sequences count as one character per byte. Every length limit in this module is checked with CharCount
+*/
+func CharCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+/*
+CheckValidIconURL checks that discord will accept the given url in the restricted fields, which take http(s) urls and
+`attachment://` urls. See ValidateURL for the exact rules
+*/
 func CheckValidIconURL(url string) bool {
-	for _, pfx := range acceptablePrefixes {
-		if strings.HasPrefix(url, pfx) {
-			return true
+	return ValidateURL(``, url, true, DefaultLimits) == nil
+}
+
+/*
+CheckValidAttachmentName checks that name can be referenced with an `attachment://` url. Discord only matches
+attachment names made up of letters, digits, `_`, `-` and `.`, and the name must have a file extension
+*/
+func CheckValidAttachmentName(name string) bool {
+	return attachmentName.MatchString(name)
+}
+
+/*
+ValidateURL checks that discord will accept rawURL in the property at path, returning nil if it will. http(s) urls must
+parse, must not contain whitespace and must have a host. If allowAttachment is true `attachment://` urls naming a valid
+attachment (see CheckValidAttachmentName) are also accepted. Urls longer than limits.URL characters (MaxURLCharLimit if
+it is 0) are rejected with ErrTooLong, all other problems are reported with ErrInvalidURL
+*/
+func ValidateURL(path string, rawURL string, allowAttachment bool, limits Limits) *Error {
+	if length := CharCount(rawURL); length > limits.urlLimit() {
+		return NewError(path, ErrTooLong, limits.urlLimit(), length)
+	}
+
+	if strings.HasPrefix(rawURL, AttachmentURLPrefix) {
+		if allowAttachment && CheckValidAttachmentName(strings.TrimPrefix(rawURL, AttachmentURLPrefix)) {
+			return nil
 		}
+		return NewError(path, ErrInvalidURL, nil, rawURL)
 	}
-	return false
+
+	if strings.IndexFunc(rawURL, unicode.IsSpace) >= 0 {
+		return NewError(path, ErrInvalidURL, nil, rawURL)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != `https` && u.Scheme != `http`) || u.Hostname() == `` {
+		return NewError(path, ErrInvalidURL, nil, rawURL)
+	}
+	return nil
 }
 
-var (
-	// A list of the prefixes specific discord URLs will accept
-	acceptablePrefixes = [3]string{
-		`https://`, // Prefer `https://`
-		`attachment://`,
-		`http://`,
+/*
+LintURL checks a url that ValidateURL has accepted for practices discord allows but that are best avoided, returning a
+warning-level error if rawURL uses http:// instead of https://, or nil otherwise
+*/
+func LintURL(path string, rawURL string) *Error {
+	if u, err := url.Parse(rawURL); err == nil && strings.EqualFold(u.Scheme, `http`) {
+		return NewWarning(path, ErrInsecureURL, nil, rawURL)
 	}
+	return nil
+}
+
+var (
+	// attachmentName matches the filenames discord will resolve from an `attachment://` url
+	attachmentName = regexp.MustCompile(`^[A-Za-z0-9_.-]*[A-Za-z0-9_-]\.[A-Za-z0-9]+$`)
+)
+
+const (
+	// AttachmentURLPrefix is the prefix used to reference a file attached to the message
+	AttachmentURLPrefix = `attachment://`
 )
 
 const (
@@ -41,31 +106,60 @@ const (
 
 	// MaxColorValue is the largest acceptable colour value
 	MaxColorValue = 16777215
+
+	// MaxMessageContentCharLimit is the maximum number of characters in a message's content
+	MaxMessageContentCharLimit = 2000
+
+	// MaxURLCharLimit is the maximum number of characters in an embed url
+	MaxURLCharLimit = 2048
+
+	// MaxEmbedCount is the maximum number of embeds in a message
+	MaxEmbedCount = 10
 )
 
+/*
+The error template strings below were used to format errors before validation.Error was introduced. Nothing in this
+module uses them any more, and they are kept so code that references them still compiles
+*/
 const (
 	// InvalidUrlErrTemplateString : [Type Property] '[Value]' does not start with "http://" | "https://" | "attachment://"
+	//
+	// Deprecated: errors are now *Error values with the code ErrInvalidURL
 	InvalidUrlErrTemplateString = `%v '%v' does not start with "http://" | "https://" | "attachment://"`
 
 	// InvalidHWErrTemplateString :  [Type] height '[Value]' or [Type] width '[Value]' is less than or equal to 0
+	//
+	// Deprecated: errors are now *Error values with the code ErrNotPositive
 	InvalidHWErrTemplateString = `%v height '%v' or %v width '%v' is less than or equal to 0`
 
 	// CharacterCountExceedsLimitErrTemplateString : [Type Property] exceeds [Limit]: length = [Length] | '[Value]'
+	//
+	// Deprecated: errors are now *Error values with the code ErrTooLong
 	CharacterCountExceedsLimitErrTemplateString = `%v exceeds %v characters: length = %v | '%v'`
 
 	// CharacterCountExceedsLimitLongErrTemplateString : [Type Property] exceeds [Limit]: length = [Length]
+	//
+	// Deprecated: errors are now *Error values with the code ErrTooLong
 	CharacterCountExceedsLimitLongErrTemplateString = `%v exceeds %v characters: length = %v`
 
 	// FieldLimitReachedErrTemplateString : adding field '[FieldName]' would cause field count to exceed [Limit]
+	//
+	// Deprecated: errors are now *Error values with the code ErrTooMany
 	FieldLimitReachedErrTemplateString = `adding field '%v' would cause field count to exceed %v`
 
 	// InvalidEmbedTypeErrTemplateString : embed type '[Type]' is not one of "rich" | "image" | "video" | "gifv" | "link" | "article"
+	//
+	// Deprecated: errors are now *Error values with the code ErrInvalidType
 	InvalidEmbedTypeErrTemplateString = `embed type '%v' is not one of "rich" | "image" | "video" | "gifv" | "link" | "article"`
 
 	// ValueNotBetweenErrTemplateString : [Type Property] [Value] is not between [LowerLimit] and [UpperLimit]
+	//
+	// Deprecated: errors are now *Error values with the code ErrOutOfRange
 	ValueNotBetweenErrTemplateString = `%v '%v' is not between %v and %v`
 
 	// ValueIsEmptyErrString : [Type Property] should not be empty if set
+	//
+	// Deprecated: errors are now *Error values with the code ErrEmpty
 	ValueIsEmptyErrString = `%v should not be empty if set`
 )
 
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/maxatome/go-testdeep/td"
@@ -21,12 +22,21 @@ func TestCheckValidIconUrl(tt *testing.T) {
 		`localhost:8080//img1.png`:                     false,
 		`http://unsecure.server.me/song1.mp3`:          true,
 		``:                                             false,
+		`https://`:                                     false,
+		`https://exa mple.com/a.png`:                   false,
+		`attachment://`:                                false,
+		`attachment://noextension`:                     false,
+		`attachment://has space.png`:                   false,
+		`HTTPS://EXAMPLE.COM/A.PNG`:                    true,
 	}
 
 	for input, want := range urls {
 		t.Logf(` - testing url '%v'`, input)
 		t.Cmp(CheckValidIconURL(input), want)
 	}
+
+	t.Log(` - testing overlong url`)
+	t.Cmp(CheckValidIconURL(`https://example.com/`+strings.Repeat(`a`, MaxURLCharLimit)), false)
 }
 
 func TestCheckTypeValid(tt *testing.T) {
@@ -49,3 +59,83 @@ func TestCheckTypeValid(tt *testing.T) {
 		t.Cmp(CheckTypeValid(input), want)
 	}
 }
+
+func TestCharCount(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`setting up table`)
+	var strs = []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{`empty`, ``, 0},
+		{`ascii`, `hello`, 5},
+		{`japanese`, `日本語のタイトル`, 8},
+		{`accented precomposed`, "caf\u00e9", 4},
+		{`combining mark`, "cafe\u0301", 5},
+		{`emoji`, "\U0001F600", 1},
+		{`emoji with skin tone`, "\U0001F44D\U0001F3FD", 2},
+		{`emoji with variation selector`, "\u2764\ufe0f", 2},
+		{`zwj sequence`, "\U0001F469\u200d\U0001F469\u200d\U0001F467", 5},
+		{`flag`, "\U0001F1EC\U0001F1E7", 2},
+		{`mixed`, "a\U0001F600b日", 4},
+		{`invalid utf8`, "a\xffb", 3},
+	}
+
+	for _, s := range strs {
+		t.Logf(` - testing %v '%v'`, s.name, s.input)
+		t.Cmp(CharCount(s.input), s.want)
+	}
+
+	t.Log(`testing multi-byte limits`)
+	t.Cmp(CharCount(strings.Repeat(`日`, LowerCharLimit)), LowerCharLimit)
+	t.Cmp(CharCount(strings.Repeat("\U0001F600", LowerCharLimit)), LowerCharLimit)
+}
+
+func TestCheckValidAttachmentName(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`setting up map`)
+	var names = map[string]bool{
+		`image.png`:            true,
+		`my_chart-2.final.jpg`: true,
+		`SPOILER_cat.gif`:      true,
+		`noextension`:          false,
+		`.png`:                 false,
+		`trailing.`:            false,
+		`has space.png`:        false,
+		`path/to/image.png`:    false,
+		`ünicode.png`:          false,
+		``:                     false,
+	}
+
+	for input, want := range names {
+		t.Logf(` - testing name '%v'`, input)
+		t.Cmp(CheckValidAttachmentName(input), want)
+	}
+}
+
+func TestValidateURL(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test valid urls`)
+	t.Cmp(ValidateURL(`url`, `https://example.com/path?q=1#frag`, false, DefaultLimits), td.Nil())
+	t.Cmp(ValidateURL(`url`, `http://127.0.0.1:8080/`, false, DefaultLimits), td.Nil())
+	t.Cmp(ValidateURL(`url`, `attachment://image.png`, true, DefaultLimits), td.Nil())
+
+	t.Log(`2. test attachments are rejected where they are not allowed`)
+	t.Cmp(ValidateURL(`url`, `attachment://image.png`, false, DefaultLimits),
+		NewError(`url`, ErrInvalidURL, nil, `attachment://image.png`))
+
+	t.Log(`3. test malformed urls`)
+	for _, u := range []string{`https://`, `https:///path`, `ftp://example.com`, `example.com`, `https://a b.com`, `https://%zz`} {
+		t.Logf(` - testing url '%v'`, u)
+		t.Cmp(ValidateURL(`url`, u, true, DefaultLimits), NewError(`url`, ErrInvalidURL, nil, u))
+	}
+
+	t.Log(`4. test length limit`)
+	long := `https://example.com/` + strings.Repeat(`a`, MaxURLCharLimit)
+	t.Cmp(ValidateURL(`url`, long, false, DefaultLimits),
+		NewError(`url`, ErrTooLong, MaxURLCharLimit, CharCount(long)))
+}
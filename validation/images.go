@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"  // register the gif decoder for image.DecodeConfig
+	_ "image/jpeg" // register the jpeg decoder for image.DecodeConfig
+	_ "image/png"  // register the png decoder for image.DecodeConfig
+)
+
+// The image formats discord renders in embeds
+const (
+	// PNGFormat is the name of the png image format
+	PNGFormat = `png`
+
+	// JPEGFormat is the name of the jpeg image format
+	JPEGFormat = `jpeg`
+
+	// GIFFormat is the name of the gif image format
+	GIFFormat = `gif`
+
+	// WebPFormat is the name of the webp image format
+	WebPFormat = `webp`
+)
+
+/*
+ImageInfo describes an image that discord can render
+*/
+type ImageInfo struct {
+	// Format is one of PNGFormat, JPEGFormat, GIFFormat or WebPFormat
+	Format string
+
+	// Width is the width of the image in pixels
+	Width int
+
+	// Height is the height of the image in pixels
+	Height int
+}
+
+/*
+DetectImage reads the format and dimensions of the image that will be shown in the property at path from its header.
+Images that are not png, jpeg, gif or webp are rejected with ErrUnsupportedImage, and images whose header cannot be read
+are rejected with ErrCorruptImage
+*/
+func DetectImage(path string, data []byte) (ImageInfo, *Error) {
+	format := sniffImageFormat(data)
+	if format == `` {
+		return ImageInfo{}, NewError(path, ErrUnsupportedImage, nil, nil)
+	}
+
+	if format == WebPFormat {
+		width, height, ok := webPSize(data)
+		if !ok {
+			return ImageInfo{}, NewError(path, ErrCorruptImage, nil, format)
+		}
+		return ImageInfo{Format: format, Width: width, Height: height}, nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return ImageInfo{}, NewError(path, ErrCorruptImage, nil, format)
+	}
+	return ImageInfo{Format: format, Width: config.Width, Height: config.Height}, nil
+}
+
+/*
+sniffImageFormat returns the format named by the magic number at the start of data, or an empty string if it is not
+one discord renders
+*/
+func sniffImageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return PNGFormat
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return JPEGFormat
+	case bytes.HasPrefix(data, []byte(`GIF87a`)), bytes.HasPrefix(data, []byte(`GIF89a`)):
+		return GIFFormat
+	case len(data) >= 12 && bytes.Equal(data[0:4], []byte(`RIFF`)) && bytes.Equal(data[8:12], []byte(`WEBP`)):
+		return WebPFormat
+	default:
+		return ``
+	}
+}
+
+/*
+webPSize reads the canvas size of a webp image from its first chunk, which is one of the lossy (VP8), lossless (VP8L)
+or extended (VP8X) chunks
+*/
+func webPSize(data []byte) (width int, height int, ok bool) {
+	if len(data) < 30 {
+		return 0, 0, false
+	}
+	chunk := data[20:]
+
+	switch string(data[12:16]) {
+	case `VP8 `:
+		// 3 byte frame tag, then the start code and two 14 bit dimensions
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, false
+		}
+		width = int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff)
+		height = int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff)
+	case `VP8L`:
+		// 1 byte signature, then two 14 bit dimensions stored minus one
+		if chunk[0] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		width = int(bits&0x3fff) + 1
+		height = int(bits>>14&0x3fff) + 1
+	case `VP8X`:
+		// 4 bytes of flags, then two 24 bit dimensions stored minus one
+		width = int(uint32(chunk[4])|uint32(chunk[5])<<8|uint32(chunk[6])<<16) + 1
+		height = int(uint32(chunk[7])|uint32(chunk[8])<<8|uint32(chunk[9])<<16) + 1
+	default:
+		return 0, 0, false
+	}
+
+	return width, height, width > 0 && height > 0
+}
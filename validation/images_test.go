@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestDetectImage(tt *testing.T) {
+	t := td.NewT(tt)
+
+	img := image.NewRGBA(image.Rect(0, 0, 30, 20))
+	var pngData, jpegData, gifData bytes.Buffer
+	t.CmpNoError(png.Encode(&pngData, img))
+	t.CmpNoError(jpeg.Encode(&jpegData, img, nil))
+	t.CmpNoError(gif.Encode(&gifData, img, nil))
+
+	riff := func(chunk string, data ...byte) []byte {
+		res := append([]byte(`RIFF`), 0, 0, 0, 0)
+		res = append(res, `WEBP`+chunk...)
+		res = append(res, 0, 0, 0, 0)
+		return append(res, data...)
+	}
+	lossy := riff(`VP8 `, 0, 0, 0, 0x9d, 0x01, 0x2a, 30, 0, 20, 0)
+	lossless := riff(`VP8L`, 0x2f, 29, 0xc0, 0x04, 0, 0, 0, 0, 0, 0)
+	extended := riff(`VP8X`, 0, 0, 0, 0, 29, 0, 0, 19, 0, 0)
+
+	t.Log(`1. test supported formats`)
+	images := map[string][]byte{
+		PNGFormat:           pngData.Bytes(),
+		JPEGFormat:          jpegData.Bytes(),
+		GIFFormat:           gifData.Bytes(),
+		WebPFormat + ` VP8`: lossy,
+		WebPFormat + `VP8L`: lossless,
+		WebPFormat + `VP8X`: extended,
+	}
+	for name, data := range images {
+		t.Logf(` - testing %v`, name)
+		info, err := DetectImage(`image`, data)
+		t.Cmp(err, td.Nil())
+		t.Cmp(info, ImageInfo{Format: name[:len(info.Format)], Width: 30, Height: 20})
+	}
+
+	t.Log(`2. test unsupported and corrupt images`)
+	_, err := DetectImage(`image`, []byte(`BM not a bitmap really`))
+	t.Cmp(err, NewError(`image`, ErrUnsupportedImage, nil, nil))
+	_, err = DetectImage(`image`, nil)
+	t.Cmp(err, NewError(`image`, ErrUnsupportedImage, nil, nil))
+	_, err = DetectImage(`image`, pngData.Bytes()[:12])
+	t.Cmp(err, NewError(`image`, ErrCorruptImage, nil, PNGFormat))
+	_, err = DetectImage(`image`, lossy[:20])
+	t.Cmp(err, NewError(`image`, ErrCorruptImage, nil, WebPFormat))
+	_, err = DetectImage(`image`, riff(`VP8 `, 0, 0, 0, 0, 0, 0, 30, 0, 20, 0))
+	t.Cmp(err, NewError(`image`, ErrCorruptImage, nil, WebPFormat))
+}
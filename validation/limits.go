@@ -0,0 +1,102 @@
+package validation
+
+/*
+Limits describes the limits discord places on embeds. Discord has changed these limits before, so rather than relying on
+the package constants, a profile can be passed to ValidateEmbedWithLimits and to the builders' SetLimits methods
+*/
+type Limits struct {
+	// Title is the maximum number of characters in an embed title
+	Title int
+
+	// Description is the maximum number of characters in an embed description
+	Description int
+
+	// FieldName is the maximum number of characters in a field name
+	FieldName int
+
+	// FieldValue is the maximum number of characters in a field value
+	FieldValue int
+
+	// FieldCount is the maximum number of fields in an embed
+	FieldCount int
+
+	// FooterText is the maximum number of characters in footer text
+	FooterText int
+
+	// AuthorName is the maximum number of characters in an author name
+	AuthorName int
+
+	// Total is the maximum number of characters across the title, description, field names and values, footer text and
+	// author name of an embed
+	Total int
+
+	// Color is the largest acceptable colour value
+	Color int
+
+	// MessageContent is the maximum number of characters in a message's content
+	MessageContent int
+
+	// URL is the maximum number of characters in any embed url. 0 means MaxURLCharLimit, so profiles written before
+	// this limit existed keep accepting urls
+	URL int
+
+	// Embeds is the maximum number of embeds in a message. Total applies to all of a message's embeds together. 0 means
+	// MaxEmbedCount, so profiles written before this limit existed keep accepting messages
+	Embeds int
+}
+
+// urlLimit returns l.URL, or MaxURLCharLimit if it is not set
+func (l Limits) urlLimit() int {
+	if l.URL == 0 {
+		return MaxURLCharLimit
+	}
+	return l.URL
+}
+
+// embedLimit returns l.Embeds, or MaxEmbedCount if it is not set
+func (l Limits) embedLimit() int {
+	if l.Embeds == 0 {
+		return MaxEmbedCount
+	}
+	return l.Embeds
+}
+
+var (
+	// LegacyLimits are the limits this module has always enforced, with embed descriptions limited to 2048 characters.
+	// They match the package constants
+	LegacyLimits = Limits{
+		Title:          LowerCharLimit,
+		Description:    UpperCharLimit,
+		FieldName:      LowerCharLimit,
+		FieldValue:     MiddleCharLimit,
+		FieldCount:     MaxFieldCount,
+		FooterText:     UpperCharLimit,
+		AuthorName:     LowerCharLimit,
+		Total:          MaxTotalCharLimit,
+		Color:          MaxColorValue,
+		MessageContent: MaxMessageContentCharLimit,
+		URL:            MaxURLCharLimit,
+		Embeds:         MaxEmbedCount,
+	}
+
+	// CurrentLimits are the limits of the current discord API, which raised the embed description limit to 4096
+	// characters
+	CurrentLimits = Limits{
+		Title:          256,
+		Description:    4096,
+		FieldName:      256,
+		FieldValue:     1024,
+		FieldCount:     25,
+		FooterText:     2048,
+		AuthorName:     256,
+		Total:          6000,
+		Color:          16777215,
+		MessageContent: 2000,
+		URL:            2048,
+		Embeds:         10,
+	}
+
+	// DefaultLimits are the limits used by ValidateEmbed and by any builder that has not been given a profile with
+	// SetLimits. It can be reassigned to change the limits used across a whole program
+	DefaultLimits = LegacyLimits
+)
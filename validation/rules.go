@@ -0,0 +1,467 @@
+package validation
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/andersfylling/disgord"
+)
+
+/*
+Target is what a Rule checks: an embed, the message it will be sent with (which may be nil) and the limits profile it
+should be held to
+*/
+type Target struct {
+	Embed   *disgord.Embed
+	Message *disgord.Message
+	Limits  Limits
+}
+
+/*
+Rule is a single validation check. ID identifies the rule within a RuleSet so that it can be replaced or disabled, and
+Check returns the problems the rule finds in target, or nil if there are none. Problems should be *Error values created
+with NewError, NewWarning or NewInfo so that callers can filter them by severity
+
+	var ErrMentionsEveryone = errors.New(`mentions everyone`)
+
+	noEveryone := validation.NewRule(`no-everyone`, func(t validation.Target) []error {
+		if strings.Contains(t.Embed.Description, `@everyone`) {
+			return []error{validation.NewError(`description`, ErrMentionsEveryone, nil, `@everyone`)}
+		}
+		return nil
+	})
+*/
+type Rule interface {
+	ID() string
+	Check(target Target) []error
+}
+
+// funcRule is a Rule backed by a function
+type funcRule struct {
+	id    string
+	check func(target Target) []error
+}
+
+// ID returns the rule's id
+func (r funcRule) ID() string {
+	return r.id
+}
+
+// Check runs the rule's function against target
+func (r funcRule) Check(target Target) []error {
+	return r.check(target)
+}
+
+/*
+NewRule creates a Rule with the given id that runs check
+*/
+func NewRule(id string, check func(target Target) []error) Rule {
+	return funcRule{id: id, check: check}
+}
+
+// The ids of the built in rules, which can be passed to RuleSet.Disable
+const (
+	// RuleTotalLength checks the embed total character count
+	RuleTotalLength = `total-length`
+
+	// RuleTitleLength checks the title length
+	RuleTitleLength = `title-length`
+
+	// RuleDescriptionLength checks the description length
+	RuleDescriptionLength = `description-length`
+
+	// RuleFieldCount checks the number of fields
+	RuleFieldCount = `field-count`
+
+	// RuleFields checks that field names and values are not empty and are within their limits
+	RuleFields = `fields`
+
+	// RuleAuthorName checks the author name length
+	RuleAuthorName = `author-name`
+
+	// RuleFooterText checks that footer text is not empty and is within its limit
+	RuleFooterText = `footer-text`
+
+	// RuleType checks that the embed type is a known type
+	RuleType = `type`
+
+	// RuleURLs checks that every url is one discord will accept
+	RuleURLs = `urls`
+
+	// RuleInsecureURL warns about http:// urls
+	RuleInsecureURL = `insecure-url`
+
+	// RuleProxyURL warns about proxy urls, which discord ignores on input
+	RuleProxyURL = `proxy-url`
+
+	// RuleIgnoredType warns about embed types other than "rich", which clients ignore
+	RuleIgnoredType = `ignored-type`
+
+	// RuleVideo warns about videos, which discord drops from embeds sent by bots
+	RuleVideo = `video`
+
+	// RuleAttachments checks that `attachment://` urls reference files attached to the message
+	RuleAttachments = `attachments`
+
+	// RuleMessageContent checks the message content length
+	RuleMessageContent = `message-content`
+)
+
+/*
+RuleSet is an ordered collection of rules. Rules run in the order they were added, and rules can be disabled by id
+without being removed. A RuleSet is safe for concurrent use
+*/
+type RuleSet struct {
+	mu       sync.RWMutex
+	rules    []Rule
+	disabled map[string]bool
+}
+
+/*
+NewRuleSet creates a RuleSet holding the given rules
+*/
+func NewRuleSet(rules ...Rule) *RuleSet {
+	res := &RuleSet{disabled: map[string]bool{}}
+	return res.Add(rules...)
+}
+
+/*
+BuiltinRules creates a RuleSet holding the discord rules this package checks by default
+*/
+func BuiltinRules() *RuleSet {
+	return NewRuleSet(
+		NewRule(RuleTotalLength, checkTotalLength),
+		NewRule(RuleTitleLength, checkTitleLength),
+		NewRule(RuleDescriptionLength, checkDescriptionLength),
+		NewRule(RuleFieldCount, checkFieldCount),
+		NewRule(RuleFields, checkFields),
+		NewRule(RuleAuthorName, checkAuthorName),
+		NewRule(RuleFooterText, checkFooterText),
+		NewRule(RuleType, checkType),
+		NewRule(RuleURLs, checkURLs),
+		NewRule(RuleInsecureURL, checkInsecureURLs),
+		NewRule(RuleProxyURL, checkProxyURLs),
+		NewRule(RuleIgnoredType, checkIgnoredType),
+		NewRule(RuleVideo, checkVideo),
+		NewRule(RuleAttachments, checkAttachments),
+		NewRule(RuleMessageContent, checkMessageContent),
+	)
+}
+
+/*
+DefaultRules are the rules used by ValidateEmbed and by the builders. It starts out holding BuiltinRules. Use Register
+to add house rules to it, or DefaultRules.Disable to turn a built in rule off across the whole program
+*/
+var DefaultRules = BuiltinRules()
+
+/*
+Register adds rules to DefaultRules. It is intended to be called while a program starts up, such as from an init
+function
+*/
+func Register(rules ...Rule) {
+	DefaultRules.Add(rules...)
+}
+
+/*
+Add appends rules to the RuleSet. A rule with the same id as one already in the set replaces it in place. It then
+returns the pointer to the RuleSet
+*/
+func (r *RuleSet) Add(rules ...Rule) *RuleSet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rule := range rules {
+		if i := r.index(rule.ID()); i >= 0 {
+			r.rules[i] = rule
+		} else {
+			r.rules = append(r.rules, rule)
+		}
+	}
+	return r
+}
+
+/*
+Disable stops the rules with the given ids from running, then returns the pointer to the RuleSet. Ids that are not in
+the set are remembered, so a rule can be disabled before it is added
+*/
+func (r *RuleSet) Disable(ids ...string) *RuleSet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.disabled[id] = true
+	}
+	return r
+}
+
+/*
+Enable allows disabled rules with the given ids to run again, then returns the pointer to the RuleSet
+*/
+func (r *RuleSet) Enable(ids ...string) *RuleSet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		delete(r.disabled, id)
+	}
+	return r
+}
+
+/*
+Rule returns the rule with the given id, or nil if there is no such rule in the set
+*/
+func (r *RuleSet) Rule(id string) Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if i := r.index(id); i >= 0 {
+		return r.rules[i]
+	}
+	return nil
+}
+
+/*
+enabled reports whether the set holds an enabled rule with the given id
+*/
+func (r *RuleSet) enabled(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.disabled[id] && r.index(id) >= 0
+}
+
+/*
+IDs returns the ids of the enabled rules in the set, in the order they run
+*/
+func (r *RuleSet) IDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var res []string
+	for _, rule := range r.rules {
+		if !r.disabled[rule.ID()] {
+			res = append(res, rule.ID())
+		}
+	}
+	return res
+}
+
+/*
+Clone returns a copy of the RuleSet that can be changed without affecting the original, such as
+validation.DefaultRules.Clone().Disable(validation.RuleInsecureURL)
+*/
+func (r *RuleSet) Clone() *RuleSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := &RuleSet{
+		rules:    append([]Rule(nil), r.rules...),
+		disabled: make(map[string]bool, len(r.disabled)),
+	}
+	for id := range r.disabled {
+		res.disabled[id] = true
+	}
+	return res
+}
+
+/*
+Validate runs every enabled rule in the set, followed by extra, against the embed and returns the problems they find, or
+nil if there are none. If msg is not nil, rules that check the message (such as RuleAttachments) are run too
+*/
+func (r *RuleSet) Validate(embed *disgord.Embed, msg *disgord.Message, limits Limits, extra ...Rule) *[]error {
+	if embed == nil {
+		return nil
+	}
+	return r.run(Target{Embed: embed, Message: msg, Limits: limits}, nil, extra)
+}
+
+/*
+run runs every enabled rule in the set that include accepts the id of (or every enabled rule if include is nil),
+followed by extra, against target and returns the problems they find, or nil if there are none
+*/
+func (r *RuleSet) run(target Target, include func(id string) bool, extra []Rule) *[]error {
+	r.mu.RLock()
+	rules := make([]Rule, 0, len(r.rules)+len(extra))
+	for _, rule := range r.rules {
+		if !r.disabled[rule.ID()] && (include == nil || include(rule.ID())) {
+			rules = append(rules, rule)
+		}
+	}
+	r.mu.RUnlock()
+	rules = append(rules, extra...)
+
+	var errs []error
+	for _, rule := range rules {
+		errs = append(errs, rule.Check(target)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &errs
+}
+
+/*
+index returns the position of the rule with the given id, or -1 if there is none. The caller must hold the lock
+*/
+func (r *RuleSet) index(id string) int {
+	for i, rule := range r.rules {
+		if rule.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
+
+/*
+checkLength returns an ErrTooLong error for the property at path if value has more than limit characters
+*/
+func checkLength(path string, value string, limit int) []error {
+	if length := CharCount(value); length > limit {
+		return []error{NewError(path, ErrTooLong, limit, length)}
+	}
+	return nil
+}
+
+// checkTotalLength checks the characters in all title, description, field.name, field.value, footer.text, and
+// author.name fields do not exceed the total limit
+func checkTotalLength(t Target) []error {
+	if total := TotalCharacterCount(t.Embed); total > t.Limits.Total {
+		return []error{NewError(``, ErrTotalTooLong, t.Limits.Total, total)}
+	}
+	return nil
+}
+
+// checkTitleLength checks the title is within its limit
+func checkTitleLength(t Target) []error {
+	return checkLength(`title`, t.Embed.Title, t.Limits.Title)
+}
+
+// checkDescriptionLength checks the description is within its limit
+func checkDescriptionLength(t Target) []error {
+	return checkLength(`description`, t.Embed.Description, t.Limits.Description)
+}
+
+// checkFieldCount checks the embed does not have too many fields
+func checkFieldCount(t Target) []error {
+	if len(t.Embed.Fields) > t.Limits.FieldCount {
+		return []error{NewError(`fields`, ErrTooMany, t.Limits.FieldCount, len(t.Embed.Fields))}
+	}
+	return nil
+}
+
+// checkFields checks field names and values are not empty and are within their limits
+func checkFields(t Target) []error {
+	var errs []error
+	for i, f := range t.Embed.Fields {
+		if f == nil {
+			continue
+		}
+		path := fmt.Sprintf(`fields[%d]`, i)
+		if f.Name == `` {
+			errs = append(errs, NewError(path+`.name`, ErrEmpty, nil, f.Name))
+		}
+		if f.Value == `` {
+			errs = append(errs, NewError(path+`.value`, ErrEmpty, nil, f.Value))
+		}
+		errs = append(errs, checkLength(path+`.name`, f.Name, t.Limits.FieldName)...)
+		errs = append(errs, checkLength(path+`.value`, f.Value, t.Limits.FieldValue)...)
+	}
+	return errs
+}
+
+// checkAuthorName checks the author name is within its limit
+func checkAuthorName(t Target) []error {
+	if t.Embed.Author == nil {
+		return nil
+	}
+	return checkLength(`author.name`, t.Embed.Author.Name, t.Limits.AuthorName)
+}
+
+// checkFooterText checks the footer text is not empty and is within its limit
+func checkFooterText(t Target) []error {
+	if t.Embed.Footer == nil {
+		return nil
+	}
+	var errs []error
+	if t.Embed.Footer.Text == `` {
+		errs = append(errs, NewError(`footer.text`, ErrEmpty, nil, t.Embed.Footer.Text))
+	}
+	return append(errs, checkLength(`footer.text`, t.Embed.Footer.Text, t.Limits.FooterText)...)
+}
+
+// checkType checks the embed type is one of "rich" | "image" | "video" | "gifv" | "link" | "article"
+func checkType(t Target) []error {
+	if t.Embed.Type != `` && !CheckTypeValid(t.Embed.Type) {
+		return []error{NewError(`type`, ErrInvalidType, nil, t.Embed.Type)}
+	}
+	return nil
+}
+
+// checkURLs checks every url is a valid http(s) url within the url limit. Image, thumbnail and icon urls may also be
+// `attachment://` urls
+func checkURLs(t Target) []error {
+	var errs []error
+	for _, ref := range urls(t.Embed) {
+		if err := ValidateURL(ref.path, ref.url, ref.allowAttachment, t.Limits); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// checkInsecureURLs warns about valid urls that use http:// rather than https://
+func checkInsecureURLs(t Target) []error {
+	var errs []error
+	for _, ref := range urls(t.Embed) {
+		if ValidateURL(ref.path, ref.url, ref.allowAttachment, t.Limits) != nil {
+			continue
+		}
+		if warning := LintURL(ref.path, ref.url); warning != nil {
+			errs = append(errs, warning)
+		}
+	}
+	return errs
+}
+
+// checkProxyURLs warns about proxy urls, which discord ignores on input
+func checkProxyURLs(t Target) []error {
+	var errs []error
+	for _, ref := range urls(t.Embed) {
+		if ref.proxy {
+			errs = append(errs, NewWarning(ref.path, ErrIgnoredProperty, nil, ref.url))
+		}
+	}
+	return errs
+}
+
+// checkIgnoredType warns about embed types other than "rich", which clients ignore
+func checkIgnoredType(t Target) []error {
+	if t.Embed.Type != `` && t.Embed.Type != RichEmbedType && CheckTypeValid(t.Embed.Type) {
+		return []error{NewWarning(`type`, ErrIgnoredType, nil, t.Embed.Type)}
+	}
+	return nil
+}
+
+// checkVideo warns about videos, which discord drops from embeds sent by bots whatever their url
+func checkVideo(t Target) []error {
+	if t.Embed.Video != nil {
+		return []error{NewWarning(`video`, ErrIgnoredProperty, nil, t.Embed.Video.URL)}
+	}
+	return nil
+}
+
+// checkAttachments checks that all `attachment://` urls reference items attached to the message, if there is one
+func checkAttachments(t Target) []error {
+	if t.Message == nil {
+		return nil
+	}
+	var errs []error
+	for _, ref := range AttachmentRefs(t.Embed) {
+		if !hasAttachment(t.Message, ref.Name()) {
+			errs = append(errs, NewError(ref.Path, ErrMissingAttachment, nil, ref.URL))
+		}
+	}
+	return errs
+}
+
+// checkMessageContent checks the message content, if there is a message, is within its limit
+func checkMessageContent(t Target) []error {
+	if t.Message == nil {
+		return nil
+	}
+	return checkLength(`content`, t.Message.Content, t.Limits.MessageContent)
+}
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+var errMentionsEveryone = errors.New(`mentions everyone`)
+
+// noEveryone is an example house rule
+var noEveryone = NewRule(`no-everyone`, func(t Target) []error {
+	if strings.Contains(t.Embed.Description, `@everyone`) {
+		return []error{NewError(`description`, errMentionsEveryone, nil, `@everyone`)}
+	}
+	return nil
+})
+
+func TestRuleSet(tt *testing.T) {
+	t := td.NewT(tt)
+
+	embed := &disgord.Embed{URL: `http://example.com`, Description: `hi @everyone`}
+
+	t.Log(`1. test the builtin rules run in order`)
+	rules := BuiltinRules()
+	t.Cmp(rules.IDs()[0], RuleTotalLength)
+	t.Cmp(rules.Validate(embed, nil, DefaultLimits), &[]error{
+		NewWarning(`url`, ErrInsecureURL, nil, `http://example.com`),
+	})
+	t.Cmp(rules.Validate(nil, nil, DefaultLimits), td.Nil())
+
+	t.Log(`2. test builtin rules can be disabled and enabled by id`)
+	rules.Disable(RuleInsecureURL)
+	t.Cmp(rules.Validate(embed, nil, DefaultLimits), td.Nil())
+	t.Cmp(rules.IDs(), td.Not(td.Contains(RuleInsecureURL)))
+	rules.Enable(RuleInsecureURL)
+	t.Cmp(rules.IDs(), td.Contains(RuleInsecureURL))
+
+	t.Log(`3. test extra rules run after the set`)
+	t.Cmp(rules.Validate(embed, nil, DefaultLimits, noEveryone), &[]error{
+		NewWarning(`url`, ErrInsecureURL, nil, `http://example.com`),
+		NewError(`description`, errMentionsEveryone, nil, `@everyone`),
+	})
+
+	t.Log(`4. test rules with the same id replace each other`)
+	rules.Add(NewRule(RuleInsecureURL, func(Target) []error { return nil }))
+	t.Cmp(rules.Validate(embed, nil, DefaultLimits), td.Nil())
+	t.Cmp(rules.Rule(RuleInsecureURL), td.NotNil())
+	t.Cmp(rules.Rule(`missing`), td.Nil())
+
+	t.Log(`5. test clones are independent`)
+	clone := rules.Clone().Add(noEveryone)
+	t.Cmp(rules.Rule(`no-everyone`), td.Nil())
+	t.Cmp(clone.Rule(`no-everyone`), td.NotNil())
+
+	t.Log(`6. test globally registered rules are used by ValidateEmbed`)
+	defer func(old *RuleSet) { DefaultRules = old }(DefaultRules)
+	DefaultRules = BuiltinRules()
+	Register(noEveryone)
+	t.Cmp(ValidateEmbed(&disgord.Embed{Description: `@everyone`}, nil), &[]error{
+		NewError(`description`, errMentionsEveryone, nil, `@everyone`),
+	})
+}
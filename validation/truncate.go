@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultEllipsis is the marker conventionally appended to truncated values
+const DefaultEllipsis = `…`
+
+const (
+	// codeFence opens and closes a markdown code block
+	codeFence = "```"
+
+	// closingCodeFence is appended to a truncated value when the cut could not be moved out of a code block
+	closingCodeFence = "\n" + codeFence
+
+	// zeroWidthJoiner joins the characters either side of it into a single glyph, as in many emoji sequences
+	zeroWidthJoiner = '\u200d'
+)
+
+var (
+	// markdownLink matches a masked markdown link at the start of a string
+	markdownLink = regexp.MustCompile(`^\[[^\[\]\n]*\]\([^()\s]*\)`)
+)
+
+/*
+Truncate shortens s so that it is at most limit characters long (as counted by CharCount), including the ellipsis that
+is appended to mark the cut. If s already fits it is returned unchanged. Truncate never splits a character, and will not
+separate a character from the combining marks, variation selectors or zero width joiners that follow it. Where
+possible the cut is moved back so that it does not land inside a markdown code block, inline code span or masked link;
+if moving the cut out of a code block would leave nothing, the block is closed instead
+*/
+func Truncate(s string, limit int, ellipsis string) string {
+	if CharCount(s) <= limit {
+		return s
+	}
+
+	marker := []rune(ellipsis)
+	if limit <= 0 {
+		return ``
+	}
+	if len(marker) >= limit {
+		return string(marker[:limit])
+	}
+
+	runes := []rune(s)
+	source := string(runes) // re-encoded so byte offsets line up with kept, even if s is not valid UTF-8
+	cut := backOffJoiners(runes, limit-len(marker))
+	kept := string(runes[:cut])
+
+	// Move the cut out of an unclosed code block, or close the block if that would leave nothing behind
+	if fences := strings.Count(kept, codeFence); fences%2 == 1 {
+		open := strings.LastIndex(kept, codeFence)
+		if before := strings.TrimRightFunc(kept[:open], unicode.IsSpace); before != `` {
+			kept = before
+		} else {
+			cut = backOffJoiners(runes, limit-len(marker)-CharCount(closingCodeFence))
+			if cut <= CharCount(codeFence) {
+				return string(marker)
+			}
+			return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + closingCodeFence + string(marker)
+		}
+	}
+
+	// Move the cut out of an unclosed inline code span
+	if spans := strings.Count(strings.ReplaceAll(kept, codeFence, ``), "`"); spans%2 == 1 {
+		kept = kept[:lastInlineTick(kept)]
+	}
+
+	// Move the cut out of a masked link that would otherwise be split
+	if open := strings.LastIndex(kept, `[`); open >= 0 {
+		if loc := markdownLink.FindStringIndex(source[open:]); loc != nil && open+loc[1] > len(kept) {
+			kept = kept[:open]
+		}
+	}
+
+	return strings.TrimRightFunc(kept, unicode.IsSpace) + string(marker)
+}
+
+/*
+Cut splits s into a head of at most limit characters (as counted by CharCount) and the tail that follows it. Like
+Truncate, it will not separate a character from the combining marks, variation selectors or zero width joiners that
+follow it, unless a single combined character is longer than limit, in which case it is split rather than leaving the
+head empty. Unlike Truncate, it does not add a marker or look for markdown, so the head and tail joined together always
+give back s
+*/
+func Cut(s string, limit int) (head string, tail string) {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s, ``
+	}
+	if limit <= 0 {
+		return ``, s
+	}
+	cut := backOffJoiners(runes, limit)
+	if cut == 0 {
+		cut = limit
+	}
+	return string(runes[:cut]), string(runes[cut:])
+}
+
+/*
+backOffJoiners moves cut backwards until runes[cut] is not a character that joins onto the one before it, so that
+runes[:cut] never ends part way through a combined character
+*/
+func backOffJoiners(runes []rune, cut int) int {
+	if cut <= 0 {
+		return 0
+	}
+	if cut >= len(runes) {
+		return len(runes)
+	}
+	for cut > 0 && (isJoining(runes[cut]) || runes[cut-1] == zeroWidthJoiner) {
+		cut--
+	}
+	return cut
+}
+
+/*
+isJoining checks whether r attaches to the character before it rather than standing alone
+*/
+func isJoining(r rune) bool {
+	return r == zeroWidthJoiner ||
+		unicode.Is(unicode.Mn, r) ||
+		unicode.Is(unicode.Me, r) ||
+		unicode.Is(unicode.Variation_Selector, r) ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) // emoji skin tone modifiers
+}
+
+/*
+lastInlineTick returns the byte index of the last backtick in s that is not part of a code fence
+*/
+func lastInlineTick(s string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] != '`' {
+			continue
+		}
+		if strings.HasSuffix(s[:i+1], codeFence) {
+			i -= len(codeFence) - 1
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestTruncate(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`setting up table`)
+	var cases = []struct {
+		name     string
+		input    string
+		limit    int
+		ellipsis string
+		want     string
+	}{
+		{`fits`, `hello`, 5, DefaultEllipsis, `hello`},
+		{`plain cut`, `hello world`, 8, DefaultEllipsis, `hello w…`},
+		{`no marker`, `hello world`, 5, ``, `hello`},
+		{`trailing space trimmed`, `hello world`, 7, DefaultEllipsis, `hello…`},
+		{`marker longer than limit`, `hello world`, 2, `...`, `..`},
+		{`multi-byte`, `日本語のタイトル`, 4, DefaultEllipsis, `日本語…`},
+		{`emoji`, "\U0001F600\U0001F600\U0001F600\U0001F600", 3, DefaultEllipsis, "\U0001F600\U0001F600…"},
+		{`combining mark kept with base`, "abcde\u0301f", 6, DefaultEllipsis, `abcd…`},
+		{`zwj sequence not split`, "ab\U0001F469\u200d\U0001F467cd", 5, DefaultEllipsis, `ab…`},
+		{`code block moved`, "intro\n```go\nfunc main() {}\n```", 20, DefaultEllipsis, `intro…`},
+		{`code block closed`, "```\naaaaaaaaaaaaaaaaaaaa\n```", 15, DefaultEllipsis, "```\naaaaaa\n```…"},
+		{`inline code moved`, "see `some code here` now", 14, DefaultEllipsis, `see…`},
+		{`link moved`, `read [the docs](https://example.com) please`, 20, DefaultEllipsis, `read…`},
+		{`complete link kept`, `read [docs](https://a.b) please now`, 30, DefaultEllipsis, `read [docs](https://a.b) plea…`},
+	}
+
+	for _, c := range cases {
+		t.Logf(` - testing %v`, c.name)
+		got := Truncate(c.input, c.limit, c.ellipsis)
+		t.Cmp(got, c.want)
+		t.Cmp(CharCount(got) <= c.limit, true)
+	}
+
+	t.Log(`testing long values always fit`)
+	long := strings.Repeat("```\n"+strings.Repeat(`x`, 100)+"\n```\n", 50)
+	t.Cmp(CharCount(Truncate(long, UpperCharLimit, DefaultEllipsis)) <= UpperCharLimit, true)
+}
+
+func TestCut(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`setting up table`)
+	var cases = []struct {
+		name  string
+		input string
+		limit int
+		head  string
+		tail  string
+	}{
+		{`fits`, `hello`, 5, `hello`, ``},
+		{`plain cut`, `hello world`, 5, `hello`, ` world`},
+		{`no room`, `hello`, 0, ``, `hello`},
+		{`combining mark kept with base`, "abcde\u0301f", 5, `abcd`, "e\u0301f"},
+		{`zwj sequence not split`, "ab\U0001F469\u200d\U0001F467cd", 4, `ab`, "\U0001F469\u200d\U0001F467cd"},
+		{`over long character split`, "\U0001F469\u200d\U0001F467", 2, "\U0001F469\u200d", "\U0001F467"},
+	}
+
+	for _, c := range cases {
+		t.Logf(` - testing %v`, c.name)
+		head, tail := Cut(c.input, c.limit)
+		t.Cmp(head, c.head)
+		t.Cmp(tail, c.tail)
+		t.Cmp(head+tail, c.input)
+	}
+}
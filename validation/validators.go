@@ -1,35 +1,244 @@
 package validation
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/andersfylling/disgord"
 )
 
 /*
 ValidateEmbed returns whether or not discord is likely accept the embed attached to it. If discord is unlikely to
 accept the embed, it returns a list of reasons why. If msg is not nil, the checker will also validate `attachment://`
-urls
-*/
-func ValidateEmbed(embed *disgord.Embed, msg *disgord.Message) *[]error {
-	// TODO: Write this
-	/* To Check:
-	 *   1) The characters in all title, description, field.name, field.value, footer.text, and author.name fields must
-	 *      not exceed 6000 characters in total
-	 *   2) The following fields are limited to 256 characters
-	 *      | embed title
-	 *      | field name
-	 *      | author name
-	 *   3) Field.value is limited to 1024 characters
-	 *   4) The following fields are limited to 1024 characters
-	 *      | embed description
-	 *      | footer text
-	 *   5) An embed can have a maximum of 25 attached fields
-	 *   6) EmbedBuilder types are limited to one of "rich" | "image" | "video" | "gifv" | "link" | "article"
-	 *   7) The following fields must not be empty when present
-	 *      | footer text
-	 *      | field name
-	 *      | field value
-	 *   8) (optional with presence of msg) All `attachment://` urls should reference attached items
-	 *   9) (optional with presence of msg) Message content cannot exceed 2000 characters
-	 */
-	return nil
+urls. All returned errors are *Error values with paths relative to the embed. The embed is checked against
+DefaultLimits.
+Problems discord will reject are reported at SeverityError, while bad practices it accepts (such as http:// urls or
+properties it ignores) are reported at SeverityWarning. Use HasErrors or FilterSeverity to decide which to act on.
+The embed is checked by DefaultRules followed by any extra rules given for this call only
+*/
+func ValidateEmbed(embed *disgord.Embed, msg *disgord.Message, extra ...Rule) *[]error {
+	return ValidateEmbedWithLimits(embed, msg, DefaultLimits, extra...)
+}
+
+/*
+ValidateEmbedWithLimits works like ValidateEmbed, but checks the embed against the given limits profile instead of
+DefaultLimits
+*/
+func ValidateEmbedWithLimits(embed *disgord.Embed, msg *disgord.Message, limits Limits, extra ...Rule) *[]error {
+	return DefaultRules.Validate(embed, msg, limits, extra...)
+}
+
+/*
+ValidateEmbeds checks a message that carries several embeds against DefaultLimits, returning the problems found or nil
+if there are none. Each embed is checked by DefaultRules followed by any extra rules, with the paths of its problems
+placed beneath `embeds[i]`, so `attachment://` urls that do not reference a file attached to msg are reported per
+embed by RuleAttachments. Rules that check the message rather than an embed, such as RuleMessageContent, are run once.
+The message as a whole is then checked for
+  - the number of embeds
+  - the combined character count of all embeds, which shares a single Limits.Total budget
+  - `attachment://` urls referencing a file already used by an earlier embed, reported as a warning (unless
+    RuleAttachments is disabled)
+*/
+func ValidateEmbeds(embeds []*disgord.Embed, msg *disgord.Message, extra ...Rule) *[]error {
+	return ValidateEmbedsWithLimits(embeds, msg, DefaultLimits, DefaultRules, extra...)
+}
+
+/*
+ValidateEmbedsWithLimits works like ValidateEmbeds, but checks the embeds against the given limits profile instead of
+DefaultLimits, and with the given RuleSet instead of DefaultRules. A nil RuleSet means DefaultRules
+*/
+func ValidateEmbedsWithLimits(
+	embeds []*disgord.Embed, msg *disgord.Message, limits Limits, rules *RuleSet, extra ...Rule,
+) *[]error {
+	if rules == nil {
+		rules = DefaultRules
+	}
+	var errs []error
+
+	if len(embeds) > limits.embedLimit() {
+		errs = append(errs, NewError(`embeds`, ErrTooMany, limits.embedLimit(), len(embeds)))
+	}
+
+	combined := 0
+	usedBy := map[string]int{}
+	checkDuplicates := rules.enabled(RuleAttachments)
+	for i, embed := range embeds {
+		if embed == nil {
+			continue
+		}
+		prefix := fmt.Sprintf(`embeds[%d]`, i)
+
+		// Message rules are run once below rather than once per embed
+		target := Target{Embed: embed, Message: msg, Limits: limits}
+		if embedErrs := PrefixErrors(prefix, rules.run(target, isEmbedRule, extra)); embedErrs != nil {
+			errs = append(errs, *embedErrs...)
+		}
+		combined += TotalCharacterCount(embed)
+
+		if !checkDuplicates {
+			continue
+		}
+		for _, ref := range AttachmentRefs(embed) {
+			name := ref.Name()
+			if first, ok := usedBy[name]; ok && first != i {
+				errs = append(errs, NewWarning(prefix+`.`+ref.Path, ErrDuplicateAttachment, nil, ref.URL))
+			} else if !ok {
+				usedBy[name] = i
+			}
+		}
+	}
+
+	if combined > limits.Total {
+		errs = append(errs, NewError(`embeds`, ErrCombinedTooLong, limits.Total, combined))
+	}
+
+	// Message rules are given an empty embed, as they check the message rather than any one embed
+	target := Target{Embed: &disgord.Embed{}, Message: msg, Limits: limits}
+	if msgErrs := rules.run(target, isMessageRule, nil); msgErrs != nil {
+		errs = append(errs, *msgErrs...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &errs
+}
+
+// messageRules holds the ids of the built in rules that check the message as a whole rather than an embed
+var messageRules = map[string]bool{RuleMessageContent: true}
+
+// isMessageRule reports whether the rule with the given id checks the message as a whole
+func isMessageRule(id string) bool {
+	return messageRules[id]
+}
+
+// isEmbedRule reports whether the rule with the given id checks a single embed
+func isEmbedRule(id string) bool {
+	return !messageRules[id]
+}
+
+/*
+TotalCharacterCount returns the number of characters discord counts towards the embed's total character limit
+(Limits.Total). This is the sum of the title, description, field names and values, footer text and author name
+*/
+func TotalCharacterCount(embed *disgord.Embed) int {
+	if embed == nil {
+		return 0
+	}
+
+	total := CharCount(embed.Title) + CharCount(embed.Description)
+	for _, f := range embed.Fields {
+		if f != nil {
+			total += CharCount(f.Name) + CharCount(f.Value)
+		}
+	}
+	if embed.Footer != nil {
+		total += CharCount(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		total += CharCount(embed.Author.Name)
+	}
+	return total
+}
+
+/*
+AttachmentRef is an `attachment://` url found in an embed, along with the path of the property it was found in
+*/
+type AttachmentRef struct {
+	Path string
+	URL  string
+}
+
+// Name returns the name of the file the url references
+func (r AttachmentRef) Name() string {
+	return strings.TrimPrefix(r.URL, AttachmentURLPrefix)
+}
+
+/*
+AttachmentRefs returns all `attachment://` urls in the embed along with the path of the property they were found in.
+Proxy urls are ignored as discord does not read them on input
+*/
+func AttachmentRefs(embed *disgord.Embed) []AttachmentRef {
+	if embed == nil {
+		return nil
+	}
+	var refs []AttachmentRef
+	add := func(path string, url string) {
+		if strings.HasPrefix(url, AttachmentURLPrefix) {
+			refs = append(refs, AttachmentRef{Path: path, URL: url})
+		}
+	}
+
+	if embed.Image != nil {
+		add(`image.url`, embed.Image.URL)
+	}
+	if embed.Thumbnail != nil {
+		add(`thumbnail.url`, embed.Thumbnail.URL)
+	}
+	if embed.Author != nil {
+		add(`author.icon_url`, embed.Author.IconURL)
+	}
+	if embed.Footer != nil {
+		add(`footer.icon_url`, embed.Footer.IconURL)
+	}
+	return refs
+}
+
+// urlRef pairs a url with the path of the property it was found in
+type urlRef struct {
+	path            string
+	url             string
+	allowAttachment bool
+	proxy           bool
+}
+
+/*
+urls returns every non-empty url in the embed along with the path of the property it was found in, whether that
+property accepts `attachment://` urls and whether it is a proxy url
+*/
+func urls(embed *disgord.Embed) []urlRef {
+	var res []urlRef
+	add := func(path string, url string, allowAttachment bool, proxy bool) {
+		if url != `` {
+			res = append(res, urlRef{path: path, url: url, allowAttachment: allowAttachment, proxy: proxy})
+		}
+	}
+
+	add(`url`, embed.URL, false, false)
+	if embed.Author != nil {
+		add(`author.url`, embed.Author.URL, false, false)
+		add(`author.icon_url`, embed.Author.IconURL, true, false)
+		add(`author.proxy_icon_url`, embed.Author.ProxyIconURL, true, true)
+	}
+	if embed.Footer != nil {
+		add(`footer.icon_url`, embed.Footer.IconURL, true, false)
+		add(`footer.proxy_icon_url`, embed.Footer.ProxyIconURL, true, true)
+	}
+	if embed.Image != nil {
+		add(`image.url`, embed.Image.URL, true, false)
+		add(`image.proxy_url`, embed.Image.ProxyURL, true, true)
+	}
+	if embed.Thumbnail != nil {
+		add(`thumbnail.url`, embed.Thumbnail.URL, true, false)
+		add(`thumbnail.proxy_url`, embed.Thumbnail.ProxyURL, true, true)
+	}
+	if embed.Video != nil {
+		add(`video.url`, embed.Video.URL, false, false)
+	}
+	if embed.Provider != nil {
+		add(`provider.url`, embed.Provider.URL, false, false)
+	}
+	return res
+}
+
+/*
+hasAttachment checks whether msg has an attachment with the given filename
+*/
+func hasAttachment(msg *disgord.Message, filename string) bool {
+	for _, a := range msg.Attachments {
+		if a != nil && a.Filename == filename {
+			return true
+		}
+	}
+	return false
 }
@@ -1,8 +1,10 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/andersfylling/disgord"
 	"github.com/maxatome/go-testdeep/td"
 )
 
@@ -10,4 +12,172 @@ func TestValidateEmbed(tt *testing.T) {
 	t := td.NewT(tt)
 	t.Log(`testing main validator function`)
 
+	t.Log(`1. test nil and empty embeds pass`)
+	t.Cmp(ValidateEmbed(nil, nil), td.Nil())
+	t.Cmp(ValidateEmbed(&disgord.Embed{}, nil), td.Nil())
+
+	t.Log(`2. test valid embed passes`)
+	valid := &disgord.Embed{
+		Title:       `title`,
+		Type:        RichEmbedType,
+		Description: `description`,
+		Fields: []*disgord.EmbedField{
+			{Name: `name`, Value: `value`},
+		},
+		Footer:    &disgord.EmbedFooter{Text: `footer`, IconURL: `attachment://icon.png`},
+		Author:    &disgord.EmbedAuthor{Name: `author`},
+		Thumbnail: &disgord.EmbedThumbnail{URL: `https://example.com/thumb.png`},
+	}
+	msg := &disgord.Message{
+		Content:     `content`,
+		Attachments: []*disgord.Attachment{{Filename: `icon.png`}},
+	}
+	t.Cmp(ValidateEmbed(valid, msg), td.Nil())
+
+	t.Log(`3. test per-property limits`)
+	long := &disgord.Embed{
+		Title:       strings.Repeat(`a`, LowerCharLimit+1),
+		Description: strings.Repeat(`a`, UpperCharLimit+1),
+		Type:        `meme`,
+		Fields: []*disgord.EmbedField{
+			{Name: ``, Value: strings.Repeat(`a`, MiddleCharLimit+1)},
+		},
+		Footer: &disgord.EmbedFooter{},
+		Author: &disgord.EmbedAuthor{Name: strings.Repeat(`a`, LowerCharLimit+1)},
+	}
+	t.Cmp(ValidateEmbed(long, nil), &[]error{
+		NewError(`title`, ErrTooLong, LowerCharLimit, LowerCharLimit+1),
+		NewError(`description`, ErrTooLong, UpperCharLimit, UpperCharLimit+1),
+		NewError(`fields[0].name`, ErrEmpty, nil, ``),
+		NewError(`fields[0].value`, ErrTooLong, MiddleCharLimit, MiddleCharLimit+1),
+		NewError(`author.name`, ErrTooLong, LowerCharLimit, LowerCharLimit+1),
+		NewError(`footer.text`, ErrEmpty, nil, ``),
+		NewError(`type`, ErrInvalidType, nil, `meme`),
+	})
+
+	t.Log(`4. test total character and field count limits`)
+	full := &disgord.Embed{}
+	for i := 0; i < MaxFieldCount+1; i++ {
+		full.Fields = append(full.Fields, &disgord.EmbedField{Name: `n`, Value: strings.Repeat(`a`, 250)})
+	}
+	t.Cmp(ValidateEmbed(full, nil), &[]error{
+		NewError(``, ErrTotalTooLong, MaxTotalCharLimit, 26*251),
+		NewError(`fields`, ErrTooMany, MaxFieldCount, MaxFieldCount+1),
+	})
+
+	t.Log(`5. test message checks`)
+	msg = &disgord.Message{Content: strings.Repeat(`a`, MaxMessageContentCharLimit+1)}
+	t.Cmp(ValidateEmbed(valid, msg), &[]error{
+		NewError(`footer.icon_url`, ErrMissingAttachment, nil, `attachment://icon.png`),
+		NewError(`content`, ErrTooLong, MaxMessageContentCharLimit, MaxMessageContentCharLimit+1),
+	})
+
+	t.Log(`6. test bad practices are reported as warnings`)
+	linted := &disgord.Embed{
+		Type:      VideoEmbedType,
+		URL:       `http://example.com`,
+		Thumbnail: &disgord.EmbedThumbnail{URL: `https://example.com/a.png`, ProxyURL: `https://proxy.example.com/a.png`},
+		Video:     &disgord.EmbedVideo{URL: `https://example.com/a.mp4`},
+	}
+	gotErrors := ValidateEmbed(linted, nil)
+	t.Cmp(gotErrors, &[]error{
+		NewWarning(`url`, ErrInsecureURL, nil, `http://example.com`),
+		NewWarning(`thumbnail.proxy_url`, ErrIgnoredProperty, nil, `https://proxy.example.com/a.png`),
+		NewWarning(`type`, ErrIgnoredType, nil, VideoEmbedType),
+		NewWarning(`video`, ErrIgnoredProperty, nil, `https://example.com/a.mp4`),
+	})
+	t.False(HasErrors(gotErrors))
+}
+
+func TestTotalCharacterCount(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Cmp(TotalCharacterCount(nil), 0)
+	t.Cmp(TotalCharacterCount(&disgord.Embed{
+		Title:       `12345`,
+		Description: `12345`,
+		URL:         `https://not.counted`,
+		Fields:      []*disgord.EmbedField{{Name: `12345`, Value: `12345`}},
+		Footer:      &disgord.EmbedFooter{Text: `12345`, IconURL: `https://not.counted`},
+		Author:      &disgord.EmbedAuthor{Name: `12345`},
+	}), 30)
+}
+
+func TestValidateEmbedWithLimits(tt *testing.T) {
+	t := td.NewT(tt)
+
+	desc := strings.Repeat(`a`, 3000)
+	embed := &disgord.Embed{Description: desc}
+
+	t.Log(`1. test the default profile rejects long descriptions`)
+	t.Cmp(ValidateEmbed(embed, nil), &[]error{
+		NewError(`description`, ErrTooLong, UpperCharLimit, 3000),
+	})
+
+	t.Log(`2. test the current profile accepts them`)
+	t.Cmp(ValidateEmbedWithLimits(embed, nil, CurrentLimits), td.Nil())
+
+	t.Log(`3. test custom profiles are respected`)
+	custom := CurrentLimits
+	custom.Title = 5
+	t.Cmp(ValidateEmbedWithLimits(&disgord.Embed{Title: `123456`}, nil, custom), &[]error{
+		NewError(`title`, ErrTooLong, 5, 6),
+	})
+
+	t.Log(`4. test unset url and embed limits fall back to the package constants`)
+	written := Limits{
+		Title: 256, Description: 4096, FieldName: 256, FieldValue: 1024, FieldCount: 25, FooterText: 2048,
+		AuthorName: 256, Total: 6000, Color: 16777215, MessageContent: 2000,
+	}
+	t.Cmp(ValidateEmbedWithLimits(&disgord.Embed{URL: `https://example.com`}, nil, written), td.Nil())
+	t.Cmp(ValidateEmbedsWithLimits([]*disgord.Embed{{Title: `a`}, {Title: `b`}}, nil, written, nil), td.Nil())
+	long := `https://example.com/` + strings.Repeat(`a`, MaxURLCharLimit)
+	t.Cmp(ValidateEmbedWithLimits(&disgord.Embed{URL: long}, nil, written), &[]error{
+		NewError(`url`, ErrTooLong, MaxURLCharLimit, CharCount(long)),
+	})
+}
+
+func TestValidateEmbeds(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test valid embeds pass`)
+	small := &disgord.Embed{Title: `title`, Image: &disgord.EmbedImage{URL: `attachment://a.png`}}
+	msg := &disgord.Message{Content: `hi`, Attachments: []*disgord.Attachment{{Filename: `a.png`}}}
+	t.Cmp(ValidateEmbeds([]*disgord.Embed{small, {Description: `desc`}}, msg), td.Nil())
+	t.Cmp(ValidateEmbeds(nil, nil), td.Nil())
+
+	t.Log(`2. test per embed problems are prefixed`)
+	t.Cmp(ValidateEmbeds([]*disgord.Embed{small, {Title: strings.Repeat(`a`, LowerCharLimit+1)}}, nil), &[]error{
+		NewError(`embeds[1].title`, ErrTooLong, LowerCharLimit, LowerCharLimit+1),
+	})
+
+	t.Log(`3. test message wide limits`)
+	var many []*disgord.Embed
+	for i := 0; i < MaxEmbedCount+1; i++ {
+		many = append(many, &disgord.Embed{Description: strings.Repeat(`a`, 1000)})
+	}
+	t.Cmp(ValidateEmbeds(many, &disgord.Message{Content: strings.Repeat(`a`, MaxMessageContentCharLimit+1)}), &[]error{
+		NewError(`embeds`, ErrTooMany, MaxEmbedCount, MaxEmbedCount+1),
+		NewError(`embeds`, ErrCombinedTooLong, MaxTotalCharLimit, 1000*(MaxEmbedCount+1)),
+		NewError(`content`, ErrTooLong, MaxMessageContentCharLimit, MaxMessageContentCharLimit+1),
+	})
+
+	t.Log(`4. test attachment references`)
+	other := &disgord.Embed{
+		Thumbnail: &disgord.EmbedThumbnail{URL: `attachment://a.png`},
+		Footer:    &disgord.EmbedFooter{Text: `text`, IconURL: `attachment://b.png`},
+	}
+	t.Cmp(ValidateEmbeds([]*disgord.Embed{small, other}, msg), &[]error{
+		NewError(`embeds[1].footer.icon_url`, ErrMissingAttachment, nil, `attachment://b.png`),
+		NewWarning(`embeds[1].thumbnail.url`, ErrDuplicateAttachment, nil, `attachment://a.png`),
+	})
+
+	t.Log(`5. test message checks can be disabled`)
+	rules := BuiltinRules().Disable(RuleAttachments, RuleMessageContent)
+	long := &disgord.Message{Content: strings.Repeat(`a`, MaxMessageContentCharLimit+1)}
+	t.Cmp(ValidateEmbedsWithLimits([]*disgord.Embed{small, other}, long, DefaultLimits, rules), td.Nil())
+	t.Cmp(ValidateEmbedsWithLimits([]*disgord.Embed{small}, long, DefaultLimits, BuiltinRules()), &[]error{
+		NewError(`embeds[0].image.url`, ErrMissingAttachment, nil, `attachment://a.png`),
+		NewError(`content`, ErrTooLong, MaxMessageContentCharLimit, MaxMessageContentCharLimit+1),
+	})
 }
@@ -1,8 +1,6 @@
 package disgobed
 
 import (
-	"fmt"
-
 	"github.com/Nightmarlin/disgobed/validation"
 	"github.com/andersfylling/disgord"
 )
@@ -14,33 +12,97 @@ the API would ignore that field if present
 type VideoBuilder struct {
 	*disgord.EmbedVideo
 	Errors *[]error
+
+	opts builderOptions
 }
 
 /*
 Finalize strips away the extra functions and returns the wrapped type. It should always be called before an thumbnail is
 attached. Finalize will also purge the error cache!
+Warnings are dropped, so a nil result means the video has no problems discord will reject
 */
 func (v *VideoBuilder) Finalize() (*disgord.EmbedVideo, *[]error) {
 	defer func(v *VideoBuilder) { v.Errors = nil }(v)
+	return v.EmbedVideo, validation.FilterSeverity(v.Errors, v.opts.failOn)
+}
+
+/*
+peek returns the wrapped type and the recorded errors without purging the error cache, so that a VideoBuilder shared between
+embeds gives its errors to every embed it is added to, and can be read from many goroutines at once
+*/
+func (v *VideoBuilder) peek() (*disgord.EmbedVideo, *[]error) {
 	return v.EmbedVideo, v.Errors
 }
 
 /*
-addError takes a message string and adds it to the error slice stored in AuthorBuilder. If the pointer is nil a new error slice
-is created. This function takes the same inputs as fmt.Sprintf
+Strict enables strict mode, in which the first invalid setter call panics with the *validation.Error describing the
+problem instead of recording it, so the offending call appears at the top of the stack trace. It then returns the
+pointer to the VideoBuilder
+*/
+func (v *VideoBuilder) Strict() *VideoBuilder {
+	v.opts.strict = true
+	return v
+}
+
+/*
+SetLimits makes the VideoBuilder check values against the given limits profile instead of validation.DefaultLimits, then
+returns the pointer to the VideoBuilder
+*/
+func (v *VideoBuilder) SetLimits(limits validation.Limits) *VideoBuilder {
+	v.opts.profile = &limits
+	return v
+}
+
+/*
+NewVideo creates and returns an empty video structure
+*/
+func NewVideo() *VideoBuilder {
+	return &VideoBuilder{
+		EmbedVideo: &disgord.EmbedVideo{},
+		Errors:     nil,
+	}
+}
+
+/*
+addError records a validation error for the property at path in the error slice stored in VideoBuilder. If the pointer is nil
+a new error slice is created. This function takes the same inputs as validation.NewError
 */
-func (v *VideoBuilder) addError(format string, values ...interface{}) {
+func (v *VideoBuilder) addError(path string, code error, limit interface{}, value interface{}) {
 	if v.Errors == nil {
 		v.Errors = &[]error{}
 	}
-	*v.Errors = append(*v.Errors, fmt.Errorf(format, values...))
+	err := validation.NewError(path, code, limit, value)
+	v.opts.check(err)
+	*v.Errors = append(*v.Errors, err)
 }
 
 /*
-SetURL sets the video source url to the value given to it then returns a pointer to the VideoBuilder structure
+addRawError takes a pre-existing error and adds it to the stored slice. If the pointer is nil a new error slice is
+created.
+*/
+func (v *VideoBuilder) addRawError(err error) {
+	v.opts.check(err)
+	if v.Errors == nil {
+		v.Errors = &[]error{}
+	}
+	*v.Errors = append(*v.Errors, err)
+}
+
+/*
+SetURL sets the video source url to the value given to it then returns a pointer to the VideoBuilder structure. The url
+must be a valid http:// or https:// url (see validation.ValidateURL), otherwise it will not be added
+http:// urls are accepted but recorded as a warning
+(This function fails silently)
 */
 func (v *VideoBuilder) SetURL(url string) *VideoBuilder {
-	v.URL = url
+	if err := validation.ValidateURL(`url`, url, false, v.opts.limits()); err == nil {
+		v.URL = url
+		if warning := validation.LintURL(`url`, url); warning != nil {
+			v.addRawError(warning)
+		}
+	} else {
+		v.addRawError(err)
+	}
 	return v
 }
 
@@ -54,7 +116,12 @@ func (v *VideoBuilder) SetHW(h int, w int) *VideoBuilder {
 		v.Height = h
 		v.Width = w
 	} else {
-		v.addError(validation.InvalidHWErrTemplateString, `video`, h, `video`, w)
+		if h <= 0 {
+			v.addError(`height`, validation.ErrNotPositive, 0, h)
+		}
+		if w <= 0 {
+			v.addError(`width`, validation.ErrNotPositive, 0, w)
+		}
 	}
 	return v
 }
@@ -68,7 +135,7 @@ func (v *VideoBuilder) SetHeight(h int) *VideoBuilder {
 	if h > 0 {
 		v.Height = h
 	} else {
-		v.addError(validation.ValueNotBetweenErrTemplateString, `video height`, h, 0, `infinity`)
+		v.addError(`height`, validation.ErrNotPositive, 0, h)
 	}
 	return v
 }
@@ -82,7 +149,24 @@ func (v *VideoBuilder) SetWidth(w int) *VideoBuilder {
 	if w > 0 {
 		v.Width = w
 	} else {
-		v.addError(validation.ValueNotBetweenErrTemplateString, `video width`, w, 0, `infinity`)
+		v.addError(`width`, validation.ErrNotPositive, 0, w)
 	}
 	return v
 }
+
+/*
+ClearURL removes the video's url, then returns the pointer to the VideoBuilder
+*/
+func (v *VideoBuilder) ClearURL() *VideoBuilder {
+	v.URL = ``
+	return v
+}
+
+/*
+ClearHW removes the video's height and width, then returns the pointer to the VideoBuilder
+*/
+func (v *VideoBuilder) ClearHW() *VideoBuilder {
+	v.Height = 0
+	v.Width = 0
+	return v
+}
@@ -0,0 +1,49 @@
+package disgobed
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+FromYAML reads an embed from a YAML document and returns an EmbedBuilder holding it. The document uses the same
+property names as the discord API, and multi-line values such as descriptions can be written as block scalars
+
+	title: Server update
+	color: "#ff8800"
+	timestamp: now
+	description: |
+	  We are moving to a new host tonight.
+	  Expect a few minutes of downtime.
+	fields:
+	  - name: When
+	    value: 22:00 UTC
+
+Colours can be written as numbers or hex strings, and timestamps as RFC 3339 timestamps or "now". Every property is set
+through the builder's setters, so invalid values are left out and recorded in the builder's errors with the path they
+were read from, such as `fields[2].value`. The returned error is only set if data is not valid YAML for an embed,
+which includes YAML with properties an embed does not have, such as a misspelt `colour`
+*/
+func FromYAML(data []byte) (*EmbedBuilder, error) {
+	var spec embedSpec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil && err != io.EOF { // An empty document is an empty embed
+		return nil, err
+	}
+	return spec.apply(NewEmbed()), nil
+}
+
+/*
+ReadYAML works like FromYAML, but reads the embed from r
+*/
+func ReadYAML(r io.Reader) (*EmbedBuilder, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromYAML(data)
+}
@@ -0,0 +1,97 @@
+package disgobed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nightmarlin/disgobed/validation"
+	"github.com/andersfylling/disgord"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+/*
+TestYAML tests embeds can be read from YAML documents
+*/
+func TestYAML(tt *testing.T) {
+	t := td.NewT(tt)
+
+	t.Log(`1. test embeds are read through the setters`)
+	embed, err := FromYAML([]byte(`
+title: Server update
+color: "#ff8800"
+timestamp: 2020-05-01T12:30:00Z
+description: |
+  We are moving to a new host tonight.
+  Expect a few minutes of downtime.
+author:
+  name: bot
+  icon_url: https://example.com/bot.png
+footer:
+  text: footer
+thumbnail:
+  url: https://example.com/t.png
+fields:
+  - name: When
+    value: 22:00 UTC
+    inline: true
+`))
+	t.CmpNoError(err)
+	gotEmbed, gotErrors := embed.Finalize()
+	t.Cmp(gotErrors, td.Nil())
+	t.Cmp(gotEmbed, &disgord.Embed{
+		Title:       `Server update`,
+		Description: "We are moving to a new host tonight.\nExpect a few minutes of downtime.\n",
+		Timestamp:   disgord.Time{Time: time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC)},
+		Color:       0xff8800,
+		Author:      &disgord.EmbedAuthor{Name: `bot`, IconURL: `https://example.com/bot.png`},
+		Footer:      &disgord.EmbedFooter{Text: `footer`},
+		Thumbnail:   &disgord.EmbedThumbnail{URL: `https://example.com/t.png`},
+		Fields:      []*disgord.EmbedField{{Name: `When`, Value: `22:00 UTC`, Inline: true}},
+	})
+
+	t.Log(`2. test "now" timestamps and numeric colours are accepted`)
+	before := time.Now()
+	embed, err = ReadYAML(strings.NewReader("timestamp: now\ncolor: 255\n"))
+	t.CmpNoError(err)
+	t.Cmp(embed.Color, 255)
+	t.Cmp(embed.Timestamp.Time, td.Between(before, time.Now()))
+
+	t.Log(`3. test invalid values are reported with their paths`)
+	embed, err = FromYAML([]byte(`
+color: "#orange"
+timestamp: tomorrow
+image:
+  url: ftp://example.com/a.png
+fields:
+  - name: ok
+    value: ok
+  - name: ""
+    value: x
+`))
+	t.CmpNoError(err)
+	_, gotErrors = embed.Finalize()
+	t.Cmp(gotErrors, &[]error{
+		validation.NewError(`timestamp`, validation.ErrInvalidTimestamp, nil, `tomorrow`),
+		validation.NewError(`color`, validation.ErrInvalidColor, nil, `#orange`),
+		validation.NewError(`fields[1].name`, validation.ErrEmpty, nil, ``),
+		validation.NewError(`image.url`, validation.ErrInvalidURL, nil, `ftp://example.com/a.png`),
+	})
+
+	embed, err = FromYAML([]byte(`color: ""`))
+	t.CmpNoError(err)
+	_, gotErrors = embed.Finalize()
+	t.Cmp(gotErrors, &[]error{validation.NewError(`color`, validation.ErrInvalidColor, nil, ``)})
+
+	t.Log(`4. test malformed yaml is rejected`)
+	_, err = FromYAML([]byte(`fields: {name: a`))
+	t.CmpError(err)
+	_, err = FromYAML([]byte("title: t\ncolour: \"#ff8800\""))
+	t.CmpError(err)
+	t.Cmp(err.Error(), td.Contains(`colour`))
+
+	t.Log(`5. test empty documents are empty embeds`)
+	embed, err = FromYAML(nil)
+	t.CmpNoError(err)
+	t.Cmp(embed.Embed, &disgord.Embed{})
+}